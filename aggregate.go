@@ -0,0 +1,224 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// An Aggregator accumulates errors and periodically flushes them as a single
+// joined error, e.g. so that per-message failures in a consumer are reported
+// once per window rather than individually. An Aggregator is safe for
+// concurrent use.
+type Aggregator struct {
+	flush     func(error)
+	timer     *time.Timer
+	flushed   *sync.Cond
+	errs      []error
+	window    time.Duration
+	threshold int
+	batches   uint64 // batches taken; guarded by mu
+	emitted   uint64 // batches emitted; guarded by flushMu
+	mu        sync.Mutex
+	flushMu   sync.Mutex
+	closed    bool
+}
+
+// NewAggregator returns a new Aggregator that calls flush with an error
+// joining the errors added since the previous flush (see [Join]) once window
+// has elapsed since the first of them was added, or once threshold errors
+// have been added, whichever occurs first. If window is not positive, errors
+// are not flushed periodically; if threshold is not positive, errors are not
+// flushed by count. Calls to flush are serialized, and are made in the order
+// in which their errors were added; flush must not call a's methods.
+func NewAggregator(window time.Duration, threshold int, flush func(error)) *Aggregator {
+	a := &Aggregator{
+		flush:     flush,
+		window:    window,
+		threshold: threshold,
+	}
+	a.flushed = sync.NewCond(&a.flushMu)
+	return a
+}
+
+// Add adds err to the errors pending in a, flushing them if a's threshold is
+// reached. Nil errors, and errors added after a has been closed, are ignored.
+func (a *Aggregator) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+
+	a.errs = append(a.errs, err)
+	switch {
+	case a.threshold > 0 && len(a.errs) >= a.threshold:
+		errs, batch := a.take()
+		a.mu.Unlock()
+		a.emit(errs, batch)
+		return
+	case a.window > 0 && a.timer == nil:
+		batch := a.batches
+		a.timer = time.AfterFunc(a.window, func() {
+			a.flushBatch(batch)
+		})
+	}
+	a.mu.Unlock()
+}
+
+// Flush immediately flushes the errors pending in a, if any. Flush returns
+// once all flushes have completed.
+func (a *Aggregator) Flush() {
+	a.mu.Lock()
+	errs, batch := a.take()
+	a.mu.Unlock()
+
+	a.emit(errs, batch)
+}
+
+// flushBatch flushes the errors pending in a if they are still those of the
+// given batch, i.e. if they have not already been flushed by a timer that
+// could no longer be stopped.
+func (a *Aggregator) flushBatch(batch uint64) {
+	a.mu.Lock()
+	if a.batches != batch {
+		a.mu.Unlock()
+		return
+	}
+	errs, _ := a.take()
+	a.mu.Unlock()
+
+	a.emit(errs, batch)
+}
+
+// Close flushes the errors pending in a, if any, and stops a; errors added
+// after Close are ignored. Close returns once all flushes have completed.
+func (a *Aggregator) Close() {
+	a.mu.Lock()
+	a.closed = true
+	errs, batch := a.take()
+	a.mu.Unlock()
+
+	a.emit(errs, batch)
+}
+
+// take returns and clears the pending errors, along with the sequence number
+// of the batch they form. If there are no pending errors, the returned
+// sequence number is that of the next batch. a.mu must be held.
+func (a *Aggregator) take() ([]error, uint64) {
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	if len(a.errs) == 0 {
+		return nil, a.batches
+	}
+
+	errs, batch := a.errs, a.batches
+	a.errs = nil
+	a.batches++
+	return errs, batch
+}
+
+// emit waits until all batches preceding the given batch have been flushed,
+// and then flushes errs, if any.
+func (a *Aggregator) emit(errs []error, batch uint64) {
+	a.flushMu.Lock()
+	defer a.flushMu.Unlock()
+	for a.emitted < batch {
+		a.flushed.Wait()
+	}
+	if len(errs) == 0 {
+		return
+	}
+
+	defer func() {
+		a.emitted++
+		a.flushed.Broadcast()
+	}()
+
+	a.flush(Join(errs...))
+}
+
+// GroupBy returns errs grouped by the key returned by keyFn for each of them,
+// e.g. by tenant or by code, with the errors in each group joined in the
+// order in which they appear in errs (see [Join]). Nil errors are skipped. To
+// group errors as they are produced, use a [Grouper].
+func GroupBy(errs []error, keyFn func(error) string) map[string]error {
+	g := NewGrouper(keyFn)
+	for _, err := range errs {
+		g.Add(err)
+	}
+	return g.Errors()
+}
+
+// A Grouper groups errors by key as they are added, as [GroupBy] does. A
+// Grouper is safe for concurrent use.
+type Grouper struct {
+	keyFn  func(error) string
+	groups map[string][]error
+	mu     sync.Mutex
+}
+
+// NewGrouper returns a new Grouper that groups errors by the key returned by
+// keyFn for each of them.
+func NewGrouper(keyFn func(error) string) *Grouper {
+	return &Grouper{
+		keyFn:  keyFn,
+		groups: make(map[string][]error),
+	}
+}
+
+// Add adds err to its group. Nil errors are ignored.
+func (g *Grouper) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	key := g.keyFn(err)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.groups[key] = append(g.groups[key], err)
+}
+
+// Errors returns the errors added so far, grouped by key, with the errors in
+// each group joined in the order in which they were added. If only one error
+// was added to a group, it is returned verbatim.
+func (g *Grouper) Errors() map[string]error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	grouped := make(map[string]error, len(g.groups))
+	for key, errs := range g.groups {
+		if len(errs) == 1 {
+			grouped[key] = errs[0]
+			continue
+		}
+		grouped[key] = Join(errs...)
+	}
+	return grouped
+}
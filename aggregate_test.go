@@ -0,0 +1,165 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestAggregator_Threshold(t *testing.T) {
+	var (
+		flushed []string
+		a       = errors.NewAggregator(0, 2, func(err error) {
+			flushed = append(flushed, err.Error())
+		})
+	)
+
+	a.Add(errors.New("a"))
+	a.Add(nil)
+	require.Empty(t, flushed)
+	a.Add(errors.New("b"))
+	require.Equal(t, []string{"a\nb"}, flushed)
+
+	a.Flush()
+	a.Add(errors.New("c"))
+	a.Flush()
+	require.Equal(t, []string{"a\nb", "c"}, flushed)
+
+	a.Add(errors.New("d"))
+	a.Close()
+	a.Add(errors.New("e"))
+	a.Add(errors.New("f"))
+	a.Flush()
+	require.Equal(t, []string{"a\nb", "c", "d"}, flushed)
+}
+
+func TestAggregator_Window(t *testing.T) {
+	var (
+		flushed = make(chan error, 2)
+		a       = errors.NewAggregator(20*time.Millisecond, 0, func(err error) {
+			flushed <- err
+		})
+	)
+	defer a.Close()
+
+	start := time.Now()
+	a.Add(errors.New("a"))
+	a.Add(errors.New("b"))
+	require.EqualError(t, <-flushed, "a\nb")
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	a.Add(errors.New("c"))
+	require.EqualError(t, <-flushed, "c")
+}
+
+func TestAggregator_Order(t *testing.T) {
+	const (
+		producers = 4
+		n         = 200
+	)
+
+	var (
+		flushed []error
+		a       = errors.NewAggregator(10*time.Microsecond, 3, func(err error) {
+			flushed = append(flushed, err.(interface{ Unwrap() []error }).Unwrap()...)
+			time.Sleep(time.Microsecond)
+		})
+		wg sync.WaitGroup
+	)
+
+	added := make([][]error, producers)
+	for p := range added {
+		added[p] = make([]error, n)
+		for i := range added[p] {
+			added[p][i] = errors.New(strconv.Itoa(p))
+		}
+
+		wg.Add(1)
+		go func(errs []error) {
+			defer wg.Done()
+			for _, err := range errs {
+				a.Add(err)
+			}
+		}(added[p])
+	}
+	wg.Wait()
+	a.Close()
+
+	// Errors added by the same goroutine must be flushed in order.
+	got := make([][]error, producers)
+	for _, err := range flushed {
+		p, _ := strconv.Atoi(err.Error()) //nolint:errcheck
+		got[p] = append(got[p], err)
+	}
+	require.Equal(t, added, got)
+}
+
+func TestGroupBy(t *testing.T) {
+	var (
+		errA = errors.NewCoded(errors.CodeNotFound, "a")
+		errB = errors.NewCoded(errors.CodeInternal, "b")
+		errC = errors.NewCoded(errors.CodeNotFound, "c")
+		errD = errors.New("d")
+	)
+
+	grouped := errors.GroupBy(
+		[]error{errA, nil, errB, errC, errD},
+		func(err error) string {
+			return errors.CodeOf(err).String()
+		},
+	)
+	require.Len(t, grouped, 3)
+	require.EqualError(t, grouped[errors.CodeNotFound.String()], "a\nc")
+	require.Equal(t, errB, grouped[errors.CodeInternal.String()])
+	require.Equal(t, errD, grouped[errors.CodeUnknown.String()])
+
+	require.Empty(t, errors.GroupBy(nil, nil))
+}
+
+func TestGrouper(t *testing.T) {
+	var (
+		g = errors.NewGrouper(func(err error) string {
+			return err.Error()
+		})
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g.Add(errors.New([]string{"even", "odd"}[i%2]))
+		}(i)
+	}
+	wg.Wait()
+
+	grouped := g.Errors()
+	require.Len(t, grouped, 2)
+	for _, err := range grouped {
+		require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 5)
+	}
+}
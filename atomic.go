@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"sync/atomic"
+)
+
+// Once holds the first non-nil error set on it, e.g. to remember the first
+// failure among several goroutines. The zero value is an empty Once ready for
+// use. A Once is safe for concurrent use and never blocks.
+type Once struct {
+	err atomic.Pointer[error]
+}
+
+// Set stores err if it is non-nil and no error has been stored yet, reporting
+// whether err was stored.
+func (o *Once) Set(err error) bool {
+	if err == nil {
+		return false
+	}
+	return o.err.CompareAndSwap(nil, &err)
+}
+
+// Err returns the error stored by [Once.Set], or nil if none has been stored.
+func (o *Once) Err() error {
+	return deref(o.err.Load())
+}
+
+// Value holds an error that can be stored and loaded atomically, e.g. so that
+// a long-lived component can publish its latest health error to concurrent
+// readers. Unlike [sync/atomic.Value], a Value may hold errors of different
+// concrete types, as well as nil. The zero value holds a nil error. A Value is
+// safe for concurrent use and never blocks.
+type Value struct {
+	err atomic.Pointer[error]
+}
+
+// Store sets the error held by v to err.
+func (v *Value) Store(err error) {
+	v.err.Store(ptrTo(err))
+}
+
+// Load returns the error held by v.
+func (v *Value) Load() error {
+	return deref(v.err.Load())
+}
+
+// Swap sets the error held by v to err and returns the error it previously
+// held.
+func (v *Value) Swap(err error) error {
+	return deref(v.err.Swap(ptrTo(err)))
+}
+
+func ptrTo(err error) *error {
+	if err == nil {
+		return nil
+	}
+	return &err
+}
+
+func deref(err *error) error {
+	if err == nil {
+		return nil
+	}
+	return *err
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestOnce(t *testing.T) {
+	var once errors.Once
+	require.NoError(t, once.Err())
+
+	require.False(t, once.Set(nil))
+	require.NoError(t, once.Err())
+
+	require.True(t, once.Set(io.EOF))
+	require.False(t, once.Set(io.ErrUnexpectedEOF))
+	require.Equal(t, io.EOF, once.Err())
+}
+
+func TestOnce_Concurrent(t *testing.T) {
+	var (
+		once errors.Once
+		set  = make(chan error, 10)
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := fmt.Errorf("error %d", i)
+			if once.Set(err) {
+				set <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(set)
+
+	require.Len(t, set, 1)
+	require.Equal(t, <-set, once.Err())
+}
+
+func TestValue(t *testing.T) {
+	var v errors.Value
+	require.NoError(t, v.Load())
+
+	v.Store(io.EOF)
+	require.Equal(t, io.EOF, v.Load())
+
+	require.Equal(t, io.EOF, v.Swap(io.ErrUnexpectedEOF))
+	require.Equal(t, io.ErrUnexpectedEOF, v.Load())
+
+	// Errors of different concrete types may be stored.
+	wrapped := fmt.Errorf("wrapped: %w", io.EOF)
+	require.Equal(t, io.ErrUnexpectedEOF, v.Swap(wrapped))
+	require.Equal(t, wrapped, v.Load())
+
+	require.Equal(t, wrapped, v.Swap(nil))
+	require.NoError(t, v.Load())
+
+	v.Store(nil)
+	require.NoError(t, v.Swap(io.EOF))
+}
+
+func TestValue_Concurrent(t *testing.T) {
+	var (
+		v  errors.Value
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			v.Store(fmt.Errorf("error %d", i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = v.Load() //nolint:errcheck
+		}()
+	}
+	wg.Wait()
+
+	require.Error(t, v.Load())
+}
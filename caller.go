@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// WithCaller returns a new error that wraps err and records the location of
+// the function that called WithCaller, which can be retrieved with [Caller].
+// Only a single program counter is captured, which is considerably cheaper
+// than capturing a full stack; the location is resolved to a file and line
+// only when it is needed. The location is included in the %+v rendering of
+// the returned error and in its structured representation (see [Describe]),
+// but not in its message. If err is nil, WithCaller returns nil.
+func WithCaller(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	return &callerError{
+		err: err,
+		pc:  pcs[0],
+	}
+}
+
+// Caller returns the location, formatted as "file:line", recorded by the
+// first error in err's chain created by [WithCaller], if any.
+func Caller(err error) (string, bool) {
+	x, ok := AsType[*callerError](err)
+	if !ok {
+		return "", false
+	}
+	return x.location(), true
+}
+
+type callerError struct {
+	err error
+	pc  uintptr
+}
+
+func (e *callerError) frame() runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{e.pc}).Next()
+	return frame
+}
+
+func (e *callerError) location() string {
+	frame := e.frame()
+	return frame.File + ":" + strconv.Itoa(frame.Line)
+}
+
+func (e *callerError) Unwrap() error {
+	return e.err
+}
+
+func (e *callerError) Error() string {
+	return e.err.Error()
+}
+
+// Format implements fmt.Formatter. The %+v verb renders the %+v rendering of
+// the wrapped error followed by the recorded function and location, in the
+// format used by runtime/debug.Stack; all other verbs render the message.
+func (e *callerError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		frame := e.frame()
+		fmt.Fprintf(s, "%+v\n%s\n\t%s:%d", e.err, frame.Function, frame.File, frame.Line)
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithCaller(t *testing.T) {
+	require.NoError(t, errors.WithCaller(nil))
+
+	err := errors.WithCaller(io.EOF)
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	caller, ok := errors.Caller(errors.Wrap(err, "wrap"))
+	require.True(t, ok)
+	require.Regexp(t, `/caller_test\.go:\d+$`, caller)
+
+	caller, ok = errors.Caller(io.EOF)
+	require.False(t, ok)
+	require.Empty(t, caller)
+}
+
+func TestWithCaller_Format(t *testing.T) {
+	err := errors.WithCaller(io.EOF)
+
+	require.Equal(t, "EOF", fmt.Sprintf("%v", err))
+	require.Equal(t, `"EOF"`, fmt.Sprintf("%q", err))
+
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, "EOF", lines[0])
+	require.Equal(t, "go.mway.dev/errors_test.TestWithCaller_Format", lines[1])
+	require.Regexp(t, regexp.MustCompile(`^\t.+/caller_test\.go:\d+$`), lines[2])
+}
+
+func TestWithCaller_FormatWrapped(t *testing.T) {
+	err := errors.Wrap(errors.WithCaller(io.EOF), "x")
+
+	require.Equal(t, "x: EOF", fmt.Sprintf("%v", err))
+	require.Equal(t, `"x: EOF"`, fmt.Sprintf("%q", err))
+
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, "x: EOF", lines[0])
+	require.Equal(t, "go.mway.dev/errors_test.TestWithCaller_FormatWrapped", lines[1])
+	require.Regexp(t, regexp.MustCompile(`^\t.+/caller_test\.go:\d+$`), lines[2])
+
+	err = errors.Join(io.ErrUnexpectedEOF, err)
+	require.Equal(t, "unexpected EOF\nx: EOF", fmt.Sprintf("%v", err))
+
+	lines = strings.Split(fmt.Sprintf("%+v", err), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "unexpected EOF", lines[0])
+	require.Equal(t, "x: EOF", lines[1])
+	require.Equal(t, "go.mway.dev/errors_test.TestWithCaller_FormatWrapped", lines[2])
+}
+
+func TestWithCaller_Describe(t *testing.T) {
+	err := errors.Wrap(errors.WithCaller(errors.New("foo")), "wrap")
+
+	node := errors.Describe(err)
+	require.Len(t, node.Causes, 1)
+	require.Equal(t, "*errors.errorString", node.Causes[0].Type)
+	require.Regexp(t, `/caller_test\.go:\d+$`, node.Causes[0].Caller)
+
+	raw, jsonErr := json.Marshal(node.Causes[0])
+	require.NoError(t, jsonErr)
+	require.Contains(t, string(raw), `"caller":`)
+}
+
+func BenchmarkWithCaller(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = errors.WithCaller(io.EOF) //nolint:errcheck
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+)
+
+// A Catalog is a machine-readable description of the errors that a program
+// may return, as produced by [ExportCatalog], e.g. for API gateways and
+// documentation generators.
+type Catalog struct {
+	// Codes describes every canonical [Code].
+	Codes []CatalogCode `json:"codes"`
+	// Sentinels describes every sentinel error registered with
+	// [RegisterSentinel], sorted by name.
+	Sentinels []CatalogSentinel `json:"sentinels"`
+}
+
+// A CatalogCode describes a [Code] in a [Catalog].
+type CatalogCode struct {
+	// Name is the code's name (see [Code.String]).
+	Name string `json:"name"`
+	// DocURL is the URL of the code's documentation, if any (see
+	// [SetDocURLFunc]).
+	DocURL string `json:"doc_url,omitempty"`
+	// HTTPStatus is the HTTP status code to which the code maps (see
+	// [Code.HTTPStatus]).
+	HTTPStatus int `json:"http_status"`
+	// Code is the code's numeric value.
+	Code Code `json:"code"`
+	// GRPCCode is the gRPC status code to which the code maps.
+	GRPCCode uint32 `json:"grpc_code"`
+}
+
+// A CatalogSentinel describes a registered sentinel error in a [Catalog].
+type CatalogSentinel struct {
+	// Name is the name with which the sentinel was registered.
+	Name string `json:"name"`
+	// Message is the sentinel's message.
+	Message string `json:"message"`
+	// Code is the name of the sentinel's code (see [CodeOf]).
+	Code string `json:"code"`
+	// DocURL is the URL of the sentinel's documentation, if any (see
+	// [SetDocURLFunc]).
+	DocURL string `json:"doc_url,omitempty"`
+	// HTTPStatus is the HTTP status code to which the sentinel maps (see
+	// [HTTPStatus]).
+	HTTPStatus int `json:"http_status"`
+	// GRPCCode is the gRPC status code to which the sentinel maps.
+	GRPCCode uint32 `json:"grpc_code"`
+}
+
+// A DocURLFunc returns the URL of the documentation for the code or
+// registered sentinel with the given name, or an empty string if there is
+// none.
+type DocURLFunc = func(name string) string
+
+var _docURLFunc atomic.Pointer[DocURLFunc]
+
+// SetDocURLFunc sets the package-level [DocURLFunc] used by [ExportCatalog]
+// to populate documentation URLs, and returns a function that restores the
+// previous function. If fn is nil, documentation URLs are omitted.
+func SetDocURLFunc(fn DocURLFunc) (restore func()) {
+	var ptr *DocURLFunc
+	if fn != nil {
+		ptr = &fn
+	}
+
+	prev := _docURLFunc.Swap(ptr)
+	return func() {
+		_docURLFunc.Store(prev)
+	}
+}
+
+func docURL(name string) string {
+	if fn := _docURLFunc.Load(); fn != nil {
+		return (*fn)(name)
+	}
+	return ""
+}
+
+// ExportCatalog returns a [Catalog] describing every canonical [Code] and
+// every sentinel error registered with [RegisterSentinel] at the time of the
+// call.
+func ExportCatalog() Catalog {
+	catalog := Catalog{
+		Codes: make([]CatalogCode, len(_codeNames)),
+	}
+	for i, name := range _codeNames {
+		code := Code(i)
+		catalog.Codes[i] = CatalogCode{
+			Name:       name,
+			DocURL:     docURL(name),
+			HTTPStatus: code.HTTPStatus(),
+			Code:       code,
+			GRPCCode:   uint32(code),
+		}
+	}
+
+	sentinels := Sentinels()
+	catalog.Sentinels = make([]CatalogSentinel, 0, len(sentinels))
+	for name, err := range sentinels {
+		code := CodeOf(err)
+		catalog.Sentinels = append(catalog.Sentinels, CatalogSentinel{
+			Name:       name,
+			Message:    err.Error(),
+			Code:       code.String(),
+			DocURL:     docURL(name),
+			HTTPStatus: HTTPStatus(err),
+			GRPCCode:   uint32(code),
+		})
+	}
+	sort.Slice(catalog.Sentinels, func(i, j int) bool {
+		return catalog.Sentinels[i].Name < catalog.Sentinels[j].Name
+	})
+
+	return catalog
+}
+
+// CatalogJSON returns the JSON encoding of the [Catalog] returned by
+// [ExportCatalog].
+func CatalogJSON() ([]byte, error) {
+	return json.Marshal(ExportCatalog())
+}
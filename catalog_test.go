@@ -0,0 +1,108 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func init() {
+	errors.RegisterSentinel(
+		"catalog_test.ErrNotFound",
+		errors.NewCoded(errors.CodeNotFound, "widget not found"),
+	)
+	errors.RegisterSentinel("catalog_test.ErrBroken", errors.Sentinel("broken"))
+}
+
+func TestExportCatalog(t *testing.T) {
+	restore := errors.SetDocURLFunc(func(name string) string {
+		if name == "catalog_test.ErrBroken" {
+			return ""
+		}
+		return "https://example.com/errors#" + name
+	})
+	defer restore()
+
+	catalog := errors.ExportCatalog()
+
+	require.Len(t, catalog.Codes, int(errors.CodeUnauthenticated)+1)
+	require.Equal(t, errors.CatalogCode{
+		Name:       "not_found",
+		DocURL:     "https://example.com/errors#not_found",
+		HTTPStatus: http.StatusNotFound,
+		Code:       errors.CodeNotFound,
+		GRPCCode:   5,
+	}, catalog.Codes[errors.CodeNotFound])
+
+	sentinels := make(map[string]errors.CatalogSentinel)
+	for i, sentinel := range catalog.Sentinels {
+		if i > 0 {
+			require.Less(t, catalog.Sentinels[i-1].Name, sentinel.Name)
+		}
+		sentinels[sentinel.Name] = sentinel
+	}
+	require.Equal(t, errors.CatalogSentinel{
+		Name:       "catalog_test.ErrNotFound",
+		Message:    "widget not found",
+		Code:       "not_found",
+		DocURL:     "https://example.com/errors#catalog_test.ErrNotFound",
+		HTTPStatus: http.StatusNotFound,
+		GRPCCode:   5,
+	}, sentinels["catalog_test.ErrNotFound"])
+	require.Equal(t, errors.CatalogSentinel{
+		Name:       "catalog_test.ErrBroken",
+		Message:    "broken",
+		Code:       "unknown",
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   2,
+	}, sentinels["catalog_test.ErrBroken"])
+
+	restore()
+	require.Empty(t, errors.ExportCatalog().Codes[errors.CodeNotFound].DocURL)
+}
+
+func TestCatalogJSON(t *testing.T) {
+	raw, err := errors.CatalogJSON()
+	require.NoError(t, err)
+
+	var catalog struct {
+		Codes []struct {
+			Name       string `json:"name"`
+			HTTPStatus int    `json:"http_status"`
+			Code       uint32 `json:"code"`
+			GRPCCode   uint32 `json:"grpc_code"`
+		} `json:"codes"`
+		Sentinels []json.RawMessage `json:"sentinels"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &catalog))
+	require.NotNil(t, catalog.Sentinels)
+
+	code := catalog.Codes[errors.CodePermissionDenied]
+	require.Equal(t, "permission_denied", code.Name)
+	require.Equal(t, http.StatusForbidden, code.HTTPStatus)
+	require.Equal(t, uint32(errors.CodePermissionDenied), code.Code)
+	require.Equal(t, uint32(errors.CodePermissionDenied), code.GRPCCode)
+}
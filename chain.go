@@ -0,0 +1,286 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// DefaultMaxDepth is the default maximum depth to which this package's
+// helpers traverse an error chain (see [SetMaxDepth]).
+const DefaultMaxDepth = 1024
+
+var (
+	_maxDepth  atomic.Int64
+	_errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// SetMaxDepth sets the maximum depth to which this package's helpers, such as
+// [AsType], [Describe], and [Fields], traverse an error chain, and returns a
+// function that restores the previous maximum depth. Errors beyond the
+// maximum depth are ignored. If n is not positive, [DefaultMaxDepth] is used.
+//
+// In addition to the depth limit, traversals stop when an error's Unwrap
+// method leads back to an error that was already visited, so that a buggy
+// error whose Unwrap method returns itself cannot cause a traversal to hang.
+// An error that wraps multiple errors is expanded at most once per
+// traversal, and a traversal of a chain that contains such errors visits at
+// most 64 times the maximum depth errors in total.
+func SetMaxDepth(n int) (restore func()) {
+	if n < 0 {
+		n = 0
+	}
+
+	prev := _maxDepth.Swap(int64(n))
+	return func() {
+		_maxDepth.Store(prev)
+	}
+}
+
+// MaxDepth returns the maximum depth to which this package's helpers traverse
+// an error chain (see [SetMaxDepth]).
+func MaxDepth() int {
+	if n := _maxDepth.Load(); n > 0 {
+		return int(n)
+	}
+	return DefaultMaxDepth
+}
+
+// Walk calls fn for each error in err's chain in depth-first order, starting
+// with err itself. Errors whose Unwrap method returns a single error are
+// followed by that error; errors whose Unwrap method returns a slice of errors
+// are followed by each of those errors and their chains, in order. If fn
+// returns false, the traversal stops. Like the traversals performed by this
+// package's other helpers, Walk is bounded by [MaxDepth] and stops following
+// an error's chain if it leads back to an error that was already visited.
+func Walk(err error, fn func(error) bool) {
+	findInChain(err, func(err error) bool {
+		return !fn(err)
+	}, newChainGuard())
+}
+
+// ChainStats describes the shape of an error chain, as returned by [Stats].
+type ChainStats struct {
+	// Nodes is the number of errors in the chain, including the error
+	// itself.
+	Nodes int
+	// MaxDepth is the length of the longest path from the error to an error
+	// that wraps no other errors (see [Depth]).
+	MaxDepth int
+	// Joins is the number of errors in the chain that wrap multiple errors,
+	// e.g. those created by [Join].
+	Joins int
+	// HasStack reports whether any error in the chain carries a stack trace
+	// (see [PanicError]).
+	HasStack bool
+	// HasFields reports whether any error in the chain carries fields (see
+	// [WithField]).
+	HasFields bool
+}
+
+// Stats returns statistics describing err's chain, e.g. to guard against or
+// observe pathologically large chains. Like the traversals performed by this
+// package's other helpers, the traversal is bounded by [MaxDepth]. Errors
+// created by [Lazy] are evaluated, and are not themselves counted. If err is
+// nil, Stats returns the zero value.
+func Stats(err error) ChainStats {
+	var stats ChainStats
+	collectStats(err, &stats, newChainGuard())
+	return stats
+}
+
+// Depth returns the length of the longest path in err's chain, where an error
+// that wraps no other errors has a depth of 1. If err is nil, Depth returns 0.
+func Depth(err error) int {
+	return Stats(err).MaxDepth
+}
+
+func collectStats(err error, stats *ChainStats, guard chainGuard) {
+	for err != nil {
+		if lazy, ok := err.(*lazyError); ok {
+			err = lazy.get()
+			continue
+		}
+		if !guard.visit(err) {
+			return
+		}
+
+		stats.Nodes++
+		stats.MaxDepth = max(stats.MaxDepth, guard.depth)
+
+		switch err.(type) {
+		case *PanicError:
+			stats.HasStack = true
+		case *fieldsError:
+			stats.HasFields = true
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			if !guard.expand(err) {
+				return
+			}
+			stats.Joins++
+			for _, cause := range x.Unwrap() {
+				collectStats(cause, stats, guard.branch())
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// cycleCheckDepth is the depth after which a chainGuard starts checking for
+// cycles. Legitimate chains are rarely this deep, so this avoids the cost of
+// cycle detection in the common case.
+const cycleCheckDepth = 16
+
+// maxNodesPerDepth bounds the total number of errors visited by a traversal
+// of a chain that contains joined errors, relative to [MaxDepth].
+const maxNodesPerDepth = 64
+
+// A chainGuard protects a traversal of an error chain against chains that are
+// too deep or that contain cycles. Cycles along a single path are detected
+// with Brent's algorithm, which requires constant space. Once the traversal
+// reaches an error that wraps multiple errors, the guards of all of its
+// branches share a chainState, so that each such error is expanded at most
+// once and the traversal visits a bounded number of errors in total.
+type chainGuard struct {
+	tortoise error
+	state    *chainState
+	depth    int
+	max      int
+	power    int
+	steps    int
+}
+
+// A chainState is shared by the guards of all branches of a traversal.
+type chainState struct {
+	joins  []error
+	budget int
+}
+
+// newChainGuard returns a chainGuard for a new traversal.
+func newChainGuard() chainGuard {
+	return chainGuard{
+		max:   MaxDepth(),
+		power: 1,
+	}
+}
+
+// visit reports whether err, the next error in the chain, may be visited.
+func (g *chainGuard) visit(err error) bool {
+	if g.depth >= g.max || (g.tortoise != nil && sameError(err, g.tortoise)) {
+		return false
+	}
+	if g.state != nil {
+		if g.state.budget <= 0 {
+			return false
+		}
+		g.state.budget--
+	}
+
+	g.depth++
+	if g.depth < cycleCheckDepth {
+		return true
+	}
+
+	g.steps++
+	if g.steps == g.power {
+		g.tortoise = err
+		g.power *= 2
+		g.steps = 0
+	}
+	return true
+}
+
+// expand reports whether the causes of err, which wraps multiple errors, may
+// be traversed, i.e. whether err has not been expanded before by this
+// traversal.
+func (g *chainGuard) expand(err error) bool {
+	if g.state == nil {
+		g.state = &chainState{
+			budget: maxNodesPerDepth * g.max,
+		}
+	}
+
+	for _, join := range g.state.joins {
+		if sameError(err, join) {
+			return false
+		}
+	}
+	g.state.joins = append(g.state.joins, err)
+	return true
+}
+
+// branch returns a chainGuard for the traversal of one of the causes of an
+// error that wraps multiple errors, which must have been passed to expand.
+func (g *chainGuard) branch() chainGuard {
+	return chainGuard{
+		state: g.state,
+		depth: g.depth,
+		max:   g.max,
+		power: 1,
+	}
+}
+
+// sameError reports whether a and b are the same error. Errors whose dynamic
+// types are not comparable are never the same.
+func sameError(a error, b error) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
+
+// findInChain reports whether match returns true for any error in err's
+// chain, which is traversed depth-first.
+func findInChain(err error, match func(error) bool, guard chainGuard) bool {
+	for err != nil && guard.visit(err) {
+		if match(err) {
+			return true
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			if !guard.expand(err) {
+				return false
+			}
+			for _, cause := range x.Unwrap() {
+				if findInChain(cause, match, guard.branch()) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestSetMaxDepth(t *testing.T) {
+	require.Equal(t, errors.DefaultMaxDepth, errors.MaxDepth())
+
+	restore := errors.SetMaxDepth(3)
+	require.Equal(t, 3, errors.MaxDepth())
+
+	restoreDefault := errors.SetMaxDepth(0)
+	require.Equal(t, errors.DefaultMaxDepth, errors.MaxDepth())
+	restoreDefault()
+	require.Equal(t, 3, errors.MaxDepth())
+
+	restore()
+	require.Equal(t, errors.DefaultMaxDepth, errors.MaxDepth())
+}
+
+func TestMaxDepth_Traversal(t *testing.T) {
+	var err error = errors.WithField(testError("testError"), "foo", "bar")
+	for i := 0; i < 4; i++ {
+		err = errors.Wrap(err, "wrap")
+	}
+
+	_, ok := errors.AsType[testError](err)
+	require.True(t, ok)
+	require.Equal(t, map[string]any{"foo": "bar"}, errors.Fields(err))
+
+	restore := errors.SetMaxDepth(3)
+	defer restore()
+
+	_, ok = errors.AsType[testError](err)
+	require.False(t, ok)
+	require.Nil(t, errors.Fields(err))
+	require.Len(t, errors.Describe(err).Causes[0].Causes[0].Causes, 0)
+}
+
+func TestWalk(t *testing.T) {
+	var (
+		errA = errors.New("a")
+		errB = errors.New("b")
+		errC = errors.New("c")
+		join = errors.Join(errors.Wrap(errA, "wrap"), errB)
+		err  = fmt.Errorf("outer: %w", errors.Join(join, errC))
+	)
+
+	var visited []string
+	errors.Walk(err, func(err error) bool {
+		visited = append(visited, err.Error())
+		return true
+	})
+	require.Equal(t, []string{
+		err.Error(),
+		"wrap: a\nb\nc",
+		"wrap: a\nb",
+		"wrap: a",
+		"a",
+		"b",
+		"c",
+	}, visited)
+
+	visited = visited[:0]
+	errors.Walk(err, func(err error) bool {
+		visited = append(visited, err.Error())
+		return err != errA
+	})
+	require.Equal(t, []string{
+		err.Error(),
+		"wrap: a\nb\nc",
+		"wrap: a\nb",
+		"wrap: a",
+		"a",
+	}, visited)
+
+	errors.Walk(nil, func(error) bool {
+		require.FailNow(t, "unexpected call")
+		return true
+	})
+}
+
+func TestWalk_Cycles(t *testing.T) {
+	self := &cyclicError{}
+	self.next = self
+
+	var n int
+	errors.Walk(errors.Wrap(self, "wrap"), func(error) bool {
+		n++
+		return true
+	})
+	require.Positive(t, n)
+	require.LessOrEqual(t, n, errors.MaxDepth())
+}
+
+func TestStats(t *testing.T) {
+	cases := map[string]struct {
+		give error
+		want errors.ChainStats
+	}{
+		"nil": {
+			give: nil,
+		},
+		"single": {
+			give: io.EOF,
+			want: errors.ChainStats{Nodes: 1, MaxDepth: 1},
+		},
+		"wrapped": {
+			give: errors.Wrap(errors.WithField(io.EOF, "foo", "bar"), "wrap"),
+			want: errors.ChainStats{Nodes: 3, MaxDepth: 3, HasFields: true},
+		},
+		"joined": {
+			give: errors.Join(
+				io.EOF,
+				errors.Wrap(errors.Recover("boom"), "wrap"),
+			),
+			want: errors.ChainStats{
+				Nodes:    4,
+				MaxDepth: 3,
+				Joins:    1,
+				HasStack: true,
+			},
+		},
+		"nested joins": {
+			give: fmt.Errorf("outer: %w", errors.Join(
+				errors.Join(io.EOF, io.ErrUnexpectedEOF),
+				io.ErrClosedPipe,
+			)),
+			want: errors.ChainStats{Nodes: 6, MaxDepth: 4, Joins: 2},
+		},
+		"lazy": {
+			give: errors.Wrap(errors.Lazy(func() error {
+				return errors.WithField(io.EOF, "foo", "bar")
+			}), "wrap"),
+			want: errors.ChainStats{Nodes: 3, MaxDepth: 3, HasFields: true},
+		},
+		"lazy nil": {
+			give: errors.Lazy(func() error {
+				return nil
+			}),
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.Stats(tt.give))
+			require.Equal(t, tt.want.MaxDepth, errors.Depth(tt.give))
+		})
+	}
+}
+
+func TestDepth_MaxDepth(t *testing.T) {
+	var err error = io.EOF
+	for i := 0; i < 4; i++ {
+		err = errors.Wrap(err, "wrap")
+	}
+	require.Equal(t, 5, errors.Depth(err))
+
+	restore := errors.SetMaxDepth(3)
+	defer restore()
+	require.Equal(t, 3, errors.Depth(err))
+}
+
+func TestCycles(t *testing.T) {
+	self := &cyclicError{}
+	self.next = self
+
+	pair := &cyclicError{}
+	pair.next = &cyclicError{next: pair}
+
+	selfJoin := &cyclicJoinError{}
+	selfJoin.errs = []error{selfJoin, selfJoin, errors.Wrap(selfJoin, "wrap"), selfJoin}
+
+	// Each join contains the previous one several times, so that the chain
+	// is exponentially large if joins are expanded more than once.
+	var dag error = io.ErrUnexpectedEOF
+	for i := 0; i < 64; i++ {
+		dag = &cyclicJoinError{errs: []error{dag, dag, dag}}
+	}
+
+	cases := map[string]error{
+		"self":           self,
+		"pair":           pair,
+		"wrapped":        errors.Wrap(errors.WithField(self, "foo", "bar"), "wrap"),
+		"joined":         errors.Join(errors.New("foo"), self),
+		"not comparable": uncomparableError{},
+		"self join":      selfJoin,
+		"wrapped join":   errors.Wrap(errors.Join(io.ErrUnexpectedEOF, selfJoin), "wrap"),
+		"repeated joins": errors.Wrap(dag, "wrap"),
+	}
+
+	for name, err := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, ok := errors.AsType[testError](err)
+			require.False(t, ok)
+
+			var target testError
+			require.False(t, errors.As(err, &target))
+			require.False(t, errors.Is(err, io.EOF))
+			require.NotNil(t, errors.Describe(err))
+			require.NotEmpty(t, errors.Logfmt(err))
+			errors.Fields(err)
+			errors.Stats(err)
+			errors.Hash(err)
+		})
+	}
+}
+
+func TestCycles_SelfJoin(t *testing.T) {
+	self := &cyclicJoinError{}
+	self.errs = []error{errors.WithField(io.EOF, "foo", "bar"), self, self}
+
+	require.True(t, errors.Is(self, io.EOF))
+	require.Equal(t, map[string]any{"foo": "bar"}, errors.Fields(self))
+	require.Equal(t, errors.ChainStats{
+		Nodes:     5,
+		MaxDepth:  3,
+		Joins:     1,
+		HasFields: true,
+	}, errors.Stats(self))
+
+	node := errors.Describe(self)
+	require.Len(t, node.Causes, 3)
+	require.Empty(t, node.Causes[1].Causes)
+	require.Empty(t, node.Causes[2].Causes)
+}
+
+type cyclicError struct {
+	next error
+}
+
+func (e *cyclicError) Error() string {
+	return "cyclic"
+}
+
+func (e *cyclicError) Unwrap() error {
+	return e.next
+}
+
+type cyclicJoinError struct {
+	errs []error
+}
+
+func (e *cyclicJoinError) Error() string {
+	return "cyclic join"
+}
+
+func (e *cyclicJoinError) Unwrap() []error {
+	return e.errs
+}
+
+type uncomparableError struct {
+	causes []string
+}
+
+func (e uncomparableError) Error() string {
+	return "uncomparable"
+}
+
+func (e uncomparableError) Unwrap() error {
+	return e
+}
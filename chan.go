@@ -0,0 +1,103 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// FromChan receives from ch until it is closed and returns an error joining
+// the non-nil errors received (see [Join]). If no non-nil errors are received,
+// FromChan returns nil; if a single one is received, it is returned as-is.
+func FromChan(ch <-chan error) error {
+	var errs []error
+	for err := range ch {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return joinReceived(errs)
+}
+
+// FromChanContext is like [FromChan], but stops receiving from ch once ctx is
+// done. If ctx is done before ch is closed, ctx.Err() is joined with the
+// errors received so far.
+func FromChanContext(ctx context.Context, ch <-chan error) error {
+	var errs []error
+	for {
+		select {
+		case err, ok := <-ch:
+			if !ok {
+				return joinReceived(errs)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case <-ctx.Done():
+			return joinReceived(append(errs, ctx.Err()))
+		}
+	}
+}
+
+// Merge returns a channel that receives every value received from each of
+// chs, e.g. so that a supervisor can watch several subsystems that each report
+// errors on their own channel. The returned channel is closed once all of chs
+// are closed; nil channels are ignored. Values are forwarded as-is, and the
+// order of values from different channels is unspecified.
+func Merge(chs ...<-chan error) <-chan error {
+	var (
+		out = make(chan error)
+		wg  sync.WaitGroup
+	)
+
+	for _, ch := range chs {
+		if ch == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(ch <-chan error) {
+			defer wg.Done()
+			for err := range ch {
+				out <- err
+			}
+		}(ch)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+func joinReceived(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return Join(errs...)
+	}
+}
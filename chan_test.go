@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestFromChan(t *testing.T) {
+	cases := map[string]struct {
+		wantErr string
+		give    []error
+	}{
+		"empty": {
+			give: nil,
+		},
+		"nils": {
+			give: []error{nil, nil},
+		},
+		"single": {
+			give:    []error{nil, io.EOF},
+			wantErr: "EOF",
+		},
+		"multiple": {
+			give:    []error{io.EOF, nil, io.ErrUnexpectedEOF},
+			wantErr: "EOF\nunexpected EOF",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			ch := make(chan error, len(tt.give))
+			for _, err := range tt.give {
+				ch <- err
+			}
+			close(ch)
+
+			err := errors.FromChan(ch)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFromChanContext(t *testing.T) {
+	ch := make(chan error, 2)
+	ch <- io.EOF
+	ch <- nil
+	close(ch)
+	require.Equal(t, io.EOF, errors.FromChanContext(context.Background(), ch))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch = make(chan error)
+	done := make(chan error)
+	go func() {
+		done <- errors.FromChanContext(ctx, ch)
+	}()
+
+	ch <- io.EOF
+	cancel()
+
+	err := <-done
+	require.ErrorIs(t, err, io.EOF)
+	require.ErrorIs(t, err, context.Canceled)
+	require.EqualError(t, err, "EOF\ncontext canceled")
+}
+
+func TestMerge(t *testing.T) {
+	var (
+		a = make(chan error)
+		b = make(chan error, 2)
+	)
+	b <- io.ErrUnexpectedEOF
+	b <- nil
+	close(b)
+
+	merged := errors.Merge(a, nil, b)
+	go func() {
+		a <- io.EOF
+		close(a)
+	}()
+
+	var got []error
+	for err := range merged {
+		got = append(got, err)
+	}
+	require.ElementsMatch(t, []error{io.EOF, io.ErrUnexpectedEOF, nil}, got)
+
+	_, ok := <-errors.Merge()
+	require.False(t, ok)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"io"
+	"strconv"
+	"sync"
+)
+
+// NamedCloser returns an io.Closer that closes c, and whose failures are
+// identified by name rather than by index when closed with [CloseAll].
+func NamedCloser(name string, c io.Closer) io.Closer {
+	return &namedCloser{
+		Closer: c,
+		name:   name,
+	}
+}
+
+type namedCloser struct {
+	io.Closer
+	name string
+}
+
+// CloseAll closes each of closers in order, skipping nil closers, and returns
+// an error joining the errors returned by their Close methods. Each error is
+// wrapped with the closer's index in closers, e.g. "close 2: <error>", or with
+// its name if the closer was created with [NamedCloser], e.g. "close db:
+// <error>". If all closers close successfully, CloseAll returns nil.
+func CloseAll(closers ...io.Closer) error {
+	var errs []error
+	for i, c := range closers {
+		if c == nil {
+			continue
+		}
+
+		name := strconv.Itoa(i)
+		if named, ok := c.(*namedCloser); ok {
+			if named.Closer == nil {
+				continue
+			}
+			name = named.name
+		}
+
+		if err := c.Close(); err != nil {
+			errs = append(errs, Wrap(err, "close "+name))
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return Join(errs...)
+	}
+}
+
+// A Cleanup is a stack of cleanup functions that are run in reverse order of
+// registration, e.g. to undo a partial initialization when a later step
+// fails:
+//
+//	var cleanup errors.Cleanup
+//	defer func() {
+//		if err != nil {
+//			err = errors.Join(err, cleanup.Run())
+//		}
+//	}()
+//
+// A Cleanup is safe for concurrent use. The zero value is an empty Cleanup
+// that is ready to use.
+type Cleanup struct {
+	fns []ErrorFunc
+	mu  sync.Mutex
+}
+
+// Add registers fn to be called by Run. If fn is nil, Add has no effect.
+func (c *Cleanup) Add(fn ErrorFunc) {
+	if fn == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fns = append(c.fns, fn)
+}
+
+// AddCloser registers closer to be closed by Run. If closer is nil, AddCloser
+// has no effect.
+func (c *Cleanup) AddCloser(closer io.Closer) {
+	if closer != nil {
+		c.Add(closer.Close)
+	}
+}
+
+// Run calls the registered functions serially, in the reverse order in which
+// they were registered, and returns an error joining the non-nil errors that
+// they return, in the order they were returned. Each function is called at
+// most once: Run unregisters the functions that it calls, so the Cleanup can
+// be reused.
+func (c *Cleanup) Run() error {
+	c.mu.Lock()
+	fns := c.fns
+	c.fns = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(fns) - 1; i >= 0; i-- {
+		if err := fns[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return Join(errs...)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestCloseAll(t *testing.T) {
+	var (
+		errA   = errors.New("a")
+		errB   = errors.New("b")
+		closed []int
+		closer = func(i int, err error) io.Closer {
+			return closerFunc(func() error {
+				closed = append(closed, i)
+				return err
+			})
+		}
+	)
+
+	require.NoError(t, errors.CloseAll())
+	require.NoError(t, errors.CloseAll(nil, closer(0, nil)))
+
+	err := errors.CloseAll(
+		closer(1, nil),
+		nil,
+		closer(2, errA),
+		errors.NamedCloser("db", closer(3, errB)),
+		errors.NamedCloser("cache", nil),
+	)
+	require.EqualError(t, err, "close 2: a\nclose db: b")
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.Equal(t, []int{0, 1, 2, 3}, closed)
+
+	require.EqualError(t, errors.CloseAll(closer(4, errA)), "close 0: a")
+}
+
+func TestCleanup(t *testing.T) {
+	var (
+		cleanup errors.Cleanup
+		errA    = errors.New("a")
+		errB    = errors.New("b")
+		calls   []string
+		call    = func(name string, err error) errors.ErrorFunc {
+			return func() error {
+				calls = append(calls, name)
+				return err
+			}
+		}
+	)
+
+	require.NoError(t, cleanup.Run())
+
+	cleanup.Add(call("first", errA))
+	cleanup.Add(nil)
+	cleanup.AddCloser(nil)
+	cleanup.AddCloser(closerFunc(call("second", nil)))
+	cleanup.Add(call("third", errB))
+
+	err := cleanup.Run()
+	require.EqualError(t, err, "b\na")
+	require.Equal(t, []string{"third", "second", "first"}, calls)
+
+	require.NoError(t, cleanup.Run())
+	require.Len(t, calls, 3)
+
+	cleanup.Add(call("fourth", errA))
+	require.Equal(t, errA, cleanup.Run())
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package clouderr classifies cloud provider SDK errors as retryable or
+// permanent, so that a single retry policy can be applied across providers.
+//
+// Errors are classified by adapters. Built-in adapters recognize errors that
+// expose an AWS-style error code (ErrorCode() string, as implemented by
+// github.com/aws/smithy-go API errors), a GCP-style error reason
+// (Reason() string, as implemented by github.com/googleapis/gax-go APIError),
+// or an HTTP status code (HTTPStatusCode() int or HTTPCode() int, as
+// implemented by the AWS and GCP SDKs respectively). Additional adapters can
+// be registered with RegisterAdapter.
+package clouderr
+
+import (
+	"net/http"
+	"sync"
+
+	"go.mway.dev/errors"
+)
+
+// A Class is a provider-independent classification of a cloud SDK error.
+type Class int
+
+// Error classes.
+const (
+	// Unclassified indicates that an error could not be classified.
+	Unclassified Class = iota
+	// Throttled indicates that a request was rate limited. Throttled errors
+	// are retryable.
+	Throttled
+	// ServerError indicates that a request failed due to a server-side
+	// error. Server errors are retryable.
+	ServerError
+	// ExpiredCredentials indicates that the credentials used for a request
+	// have expired. Expired credentials are permanent errors; requests should
+	// not be retried until credentials are refreshed.
+	ExpiredCredentials
+	// ClientError indicates that a request was invalid. Client errors are
+	// permanent.
+	ClientError
+)
+
+var _classNames = [...]string{
+	Unclassified:       "unclassified",
+	Throttled:          "throttled",
+	ServerError:        "server error",
+	ExpiredCredentials: "expired credentials",
+	ClientError:        "client error",
+}
+
+// String returns a human-readable representation of c.
+func (c Class) String() string {
+	if c >= 0 && int(c) < len(_classNames) {
+		return _classNames[c]
+	}
+	return _classNames[Unclassified]
+}
+
+// Retryable reports whether errors of class c are retryable.
+func (c Class) Retryable() bool {
+	return c == Throttled || c == ServerError
+}
+
+// Permanent reports whether errors of class c are permanent, i.e. will not
+// succeed if retried as-is.
+func (c Class) Permanent() bool {
+	return c == ExpiredCredentials || c == ClientError
+}
+
+// An Adapter classifies provider-specific errors. An Adapter should return
+// false if it does not recognize err.
+type Adapter func(err error) (Class, bool)
+
+var _adapters struct {
+	list []Adapter
+	mu   sync.RWMutex
+}
+
+// RegisterAdapter registers an Adapter used to classify provider-specific
+// errors. Adapters are consulted in reverse registration order, and before
+// built-in adapters, such that later registrations take precedence.
+func RegisterAdapter(adapter Adapter) {
+	if adapter == nil {
+		panic("clouderr: RegisterAdapter adapter is nil")
+	}
+
+	_adapters.mu.Lock()
+	defer _adapters.mu.Unlock()
+
+	_adapters.list = append(_adapters.list, adapter)
+}
+
+// Classify returns the Class of err, as classified by registered adapters
+// followed by built-in adapters. If err cannot be classified, Classify
+// returns Unclassified.
+func Classify(err error) Class {
+	if err == nil {
+		return Unclassified
+	}
+
+	_adapters.mu.RLock()
+	defer _adapters.mu.RUnlock()
+
+	for i := len(_adapters.list) - 1; i >= 0; i-- {
+		if class, ok := _adapters.list[i](err); ok {
+			return class
+		}
+	}
+
+	for _, adapter := range _builtinAdapters {
+		if class, ok := adapter(err); ok {
+			return class
+		}
+	}
+
+	return Unclassified
+}
+
+// IsRetryable reports whether err is classified as retryable.
+func IsRetryable(err error) bool {
+	return Classify(err).Retryable()
+}
+
+// IsPermanent reports whether err is classified as permanent.
+func IsPermanent(err error) bool {
+	return Classify(err).Permanent()
+}
+
+var _builtinAdapters = [...]Adapter{
+	errorCodeAdapter,
+	reasonAdapter,
+	httpStatusAdapter,
+}
+
+var _errorCodes = map[string]Class{
+	// Throttling codes, as recognized by the AWS SDK's retryer.
+	"Throttling":                             Throttled,
+	"ThrottlingException":                    Throttled,
+	"ThrottledException":                     Throttled,
+	"RequestThrottledException":              Throttled,
+	"TooManyRequestsException":               Throttled,
+	"ProvisionedThroughputExceededException": Throttled,
+	"TransactionInProgressException":         Throttled,
+	"RequestLimitExceeded":                   Throttled,
+	"BandwidthLimitExceeded":                 Throttled,
+	"LimitExceededException":                 Throttled,
+	"RequestThrottled":                       Throttled,
+	"SlowDown":                               Throttled,
+	"PriorRequestNotComplete":                Throttled,
+	"EC2ThrottledException":                  Throttled,
+	// Expired credential codes.
+	"ExpiredToken":          ExpiredCredentials,
+	"ExpiredTokenException": ExpiredCredentials,
+}
+
+var _reasons = map[string]Class{
+	"RATE_LIMIT_EXCEEDED":   Throttled,
+	"rateLimitExceeded":     Throttled,
+	"userRateLimitExceeded": Throttled,
+}
+
+func errorCodeAdapter(err error) (Class, bool) {
+	var coder interface{ ErrorCode() string }
+	if !errors.As(err, &coder) {
+		return Unclassified, false
+	}
+
+	class, ok := _errorCodes[coder.ErrorCode()]
+	return class, ok
+}
+
+func reasonAdapter(err error) (Class, bool) {
+	var reasoner interface{ Reason() string }
+	if !errors.As(err, &reasoner) {
+		return Unclassified, false
+	}
+
+	class, ok := _reasons[reasoner.Reason()]
+	return class, ok
+}
+
+func httpStatusAdapter(err error) (Class, bool) {
+	var (
+		awsStatus interface{ HTTPStatusCode() int }
+		gcpStatus interface{ HTTPCode() int }
+		status    int
+	)
+
+	switch {
+	case errors.As(err, &awsStatus):
+		status = awsStatus.HTTPStatusCode()
+	case errors.As(err, &gcpStatus):
+		status = gcpStatus.HTTPCode()
+	default:
+		return Unclassified, false
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		return Throttled, true
+	case status >= http.StatusInternalServerError:
+		return ServerError, true
+	case status >= http.StatusBadRequest:
+		return ClientError, true
+	default:
+		return Unclassified, false
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package clouderr_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/clouderr"
+)
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		give error
+		want clouderr.Class
+	}{
+		"nil": {
+			give: nil,
+			want: clouderr.Unclassified,
+		},
+		"generic": {
+			give: errors.New("foo"),
+			want: clouderr.Unclassified,
+		},
+		"aws throttling": {
+			give: &awsError{code: "ThrottlingException", status: http.StatusBadRequest},
+			want: clouderr.Throttled,
+		},
+		"aws expired token": {
+			give: &awsError{code: "ExpiredTokenException", status: http.StatusBadRequest},
+			want: clouderr.ExpiredCredentials,
+		},
+		"aws 5xx": {
+			give: &awsError{code: "InternalError", status: http.StatusInternalServerError},
+			want: clouderr.ServerError,
+		},
+		"aws 4xx": {
+			give: &awsError{code: "ValidationException", status: http.StatusBadRequest},
+			want: clouderr.ClientError,
+		},
+		"aws 2xx": {
+			give: &awsError{code: "Unknown", status: http.StatusOK},
+			want: clouderr.Unclassified,
+		},
+		"gcp rate limited": {
+			give: &gcpError{reason: "RATE_LIMIT_EXCEEDED", status: http.StatusForbidden},
+			want: clouderr.Throttled,
+		},
+		"gcp 429": {
+			give: &gcpError{status: http.StatusTooManyRequests},
+			want: clouderr.Throttled,
+		},
+		"gcp 503": {
+			give: &gcpError{status: http.StatusServiceUnavailable},
+			want: clouderr.ServerError,
+		},
+		"gcp 404": {
+			give: &gcpError{status: http.StatusNotFound},
+			want: clouderr.ClientError,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := errors.Wrap(tt.give, "wrapped")
+			require.Equal(t, tt.want, clouderr.Classify(err))
+			require.Equal(t, tt.want.Retryable(), clouderr.IsRetryable(err))
+			require.Equal(t, tt.want.Permanent(), clouderr.IsPermanent(err))
+		})
+	}
+}
+
+func TestRegisterAdapter(t *testing.T) {
+	errCustom := errors.New("custom throttling")
+	clouderr.RegisterAdapter(func(err error) (clouderr.Class, bool) {
+		if errors.Is(err, errCustom) {
+			return clouderr.Throttled, true
+		}
+		return clouderr.Unclassified, false
+	})
+
+	require.True(t, clouderr.IsRetryable(errors.Wrap(errCustom, "put object")))
+	require.Panics(t, func() {
+		clouderr.RegisterAdapter(nil)
+	})
+}
+
+func TestClass(t *testing.T) {
+	require.Equal(t, "throttled", clouderr.Throttled.String())
+	require.Equal(t, "unclassified", clouderr.Class(-1).String())
+	require.Equal(t, "unclassified", clouderr.Class(1234).String())
+
+	require.True(t, clouderr.Throttled.Retryable())
+	require.True(t, clouderr.ServerError.Retryable())
+	require.False(t, clouderr.ExpiredCredentials.Retryable())
+	require.True(t, clouderr.ExpiredCredentials.Permanent())
+	require.True(t, clouderr.ClientError.Permanent())
+	require.False(t, clouderr.Unclassified.Retryable())
+	require.False(t, clouderr.Unclassified.Permanent())
+}
+
+type awsError struct {
+	code   string
+	status int
+}
+
+func (e *awsError) Error() string       { return "aws: " + e.code }
+func (e *awsError) ErrorCode() string   { return e.code }
+func (e *awsError) HTTPStatusCode() int { return e.status }
+
+type gcpError struct {
+	reason string
+	status int
+}
+
+func (e *gcpError) Error() string  { return "gcp: " + e.reason }
+func (e *gcpError) Reason() string { return e.reason }
+func (e *gcpError) HTTPCode() int  { return e.status }
@@ -0,0 +1,189 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// A Code is a machine-readable error classification. Codes mirror the
+// canonical gRPC status codes, which allows them to be mapped consistently to
+// gRPC, HTTP, and other RPC protocols.
+type Code uint32
+
+// Canonical error codes.
+const (
+	CodeOK Code = iota
+	CodeCanceled
+	CodeUnknown
+	CodeInvalidArgument
+	CodeDeadlineExceeded
+	CodeNotFound
+	CodeAlreadyExists
+	CodePermissionDenied
+	CodeResourceExhausted
+	CodeFailedPrecondition
+	CodeAborted
+	CodeOutOfRange
+	CodeUnimplemented
+	CodeInternal
+	CodeUnavailable
+	CodeDataLoss
+	CodeUnauthenticated
+)
+
+var _codeNames = [...]string{
+	CodeOK:                 "ok",
+	CodeCanceled:           "canceled",
+	CodeUnknown:            "unknown",
+	CodeInvalidArgument:    "invalid_argument",
+	CodeDeadlineExceeded:   "deadline_exceeded",
+	CodeNotFound:           "not_found",
+	CodeAlreadyExists:      "already_exists",
+	CodePermissionDenied:   "permission_denied",
+	CodeResourceExhausted:  "resource_exhausted",
+	CodeFailedPrecondition: "failed_precondition",
+	CodeAborted:            "aborted",
+	CodeOutOfRange:         "out_of_range",
+	CodeUnimplemented:      "unimplemented",
+	CodeInternal:           "internal",
+	CodeUnavailable:        "unavailable",
+	CodeDataLoss:           "data_loss",
+	CodeUnauthenticated:    "unauthenticated",
+}
+
+var _codeHTTPStatuses = [...]int{
+	CodeOK:                 http.StatusOK,
+	CodeCanceled:           499, // client closed request
+	CodeUnknown:            http.StatusInternalServerError,
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	CodeNotFound:           http.StatusNotFound,
+	CodeAlreadyExists:      http.StatusConflict,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeFailedPrecondition: http.StatusBadRequest,
+	CodeAborted:            http.StatusConflict,
+	CodeOutOfRange:         http.StatusBadRequest,
+	CodeUnimplemented:      http.StatusNotImplemented,
+	CodeInternal:           http.StatusInternalServerError,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeDataLoss:           http.StatusInternalServerError,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+}
+
+// String returns the snake_case name of the code, e.g. "not_found".
+func (c Code) String() string {
+	if int(c) < len(_codeNames) {
+		return _codeNames[c]
+	}
+	return "code_" + strconv.FormatUint(uint64(c), 10)
+}
+
+// ParseCode returns the Code whose name, as returned by [Code.String], is
+// equal to s, ignoring case.
+func ParseCode(s string) (Code, bool) {
+	for i, name := range _codeNames {
+		if strings.EqualFold(name, s) {
+			return Code(i), true
+		}
+	}
+	return CodeUnknown, false
+}
+
+// HTTPStatus returns the HTTP status code that corresponds to c. Unrecognized
+// codes map to http.StatusInternalServerError.
+func (c Code) HTTPStatus() int {
+	if int(c) < len(_codeHTTPStatuses) {
+		return _codeHTTPStatuses[c]
+	}
+	return http.StatusInternalServerError
+}
+
+// NewCoded returns a new error that formats as msg and carries the given
+// code. Like [New], each call to NewCoded returns a distinct error value, and
+// thus NewCoded is suitable for declaring sentinel errors:
+//
+//	var ErrNotFound = errors.NewCoded(errors.CodeNotFound, "not found")
+//
+// The code of an error, or of any error that wraps it, can be retrieved with
+// [CodeOf].
+func NewCoded(code Code, msg string) error {
+	return &codedError{
+		msg:  msg,
+		code: code,
+	}
+}
+
+// CodeOf returns the code of the first error in err's chain that carries one.
+// If err is nil, CodeOf returns CodeOK. If err does not carry a code, CodeOf
+// returns CodeCanceled or CodeDeadlineExceeded if err contains
+// context.Canceled or context.DeadlineExceeded, respectively, and CodeUnknown
+// otherwise.
+func CodeOf(err error) Code {
+	if LazyOrNil(err) == nil {
+		return CodeOK
+	}
+
+	var coder interface{ Code() Code }
+	switch {
+	case As(err, &coder):
+		return coder.Code()
+	case Is(err, context.Canceled):
+		return CodeCanceled
+	case Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	default:
+		return CodeUnknown
+	}
+}
+
+// HTTPStatus derives an HTTP status code from err. If err is nil, HTTPStatus
+// returns http.StatusOK. If an error in err's chain has an HTTPStatus() int
+// method, the first such status is returned; otherwise, the status of err's
+// code is returned (see [CodeOf] and [Code.HTTPStatus]).
+func HTTPStatus(err error) int {
+	if LazyOrNil(err) == nil {
+		return http.StatusOK
+	}
+
+	var statuser interface{ HTTPStatus() int }
+	if As(err, &statuser) {
+		return statuser.HTTPStatus()
+	}
+	return CodeOf(err).HTTPStatus()
+}
+
+type codedError struct {
+	msg  string
+	code Code
+}
+
+func (e *codedError) Error() string {
+	return e.msg
+}
+
+func (e *codedError) Code() Code {
+	return e.code
+}
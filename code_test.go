@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestNewCoded(t *testing.T) {
+	var (
+		errA = errors.NewCoded(errors.CodeNotFound, "not found")
+		errB = errors.NewCoded(errors.CodeNotFound, "not found")
+	)
+
+	require.Equal(t, "not found", errA.Error())
+	require.NotErrorIs(t, errA, errB)
+	require.Equal(t, errors.CodeNotFound, errors.CodeOf(errA))
+	require.Equal(t, errors.CodeNotFound, errors.CodeOf(errors.Wrap(errA, "wrapped")))
+}
+
+func TestCodeOf(t *testing.T) {
+	cases := map[string]struct {
+		give error
+		want errors.Code
+	}{
+		"nil": {
+			give: nil,
+			want: errors.CodeOK,
+		},
+		"uncoded": {
+			give: errors.New("foo"),
+			want: errors.CodeUnknown,
+		},
+		"canceled": {
+			give: errors.Wrap(context.Canceled, "wrapped"),
+			want: errors.CodeCanceled,
+		},
+		"deadline exceeded": {
+			give: errors.Wrap(context.DeadlineExceeded, "wrapped"),
+			want: errors.CodeDeadlineExceeded,
+		},
+		"coded": {
+			give: errors.NewCoded(errors.CodeUnavailable, "unavailable"),
+			want: errors.CodeUnavailable,
+		},
+		"outermost code": {
+			give: errors.Join(
+				errors.NewCoded(errors.CodeAborted, "aborted"),
+				errors.NewCoded(errors.CodeInternal, "internal"),
+			),
+			want: errors.CodeAborted,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.CodeOf(tt.give))
+		})
+	}
+}
+
+func TestCode_String(t *testing.T) {
+	require.Equal(t, "ok", errors.CodeOK.String())
+	require.Equal(t, "not_found", errors.CodeNotFound.String())
+	require.Equal(t, "unauthenticated", errors.CodeUnauthenticated.String())
+	require.Equal(t, "code_1234", errors.Code(1234).String())
+}
+
+func TestParseCode(t *testing.T) {
+	for code := errors.CodeOK; code <= errors.CodeUnauthenticated; code++ {
+		parsed, ok := errors.ParseCode(code.String())
+		require.True(t, ok)
+		require.Equal(t, code, parsed)
+	}
+
+	parsed, ok := errors.ParseCode("NOT_FOUND")
+	require.True(t, ok)
+	require.Equal(t, errors.CodeNotFound, parsed)
+
+	parsed, ok = errors.ParseCode("bogus")
+	require.False(t, ok)
+	require.Equal(t, errors.CodeUnknown, parsed)
+}
+
+func TestCode_HTTPStatus(t *testing.T) {
+	require.Equal(t, http.StatusOK, errors.CodeOK.HTTPStatus())
+	require.Equal(t, http.StatusNotFound, errors.CodeNotFound.HTTPStatus())
+	require.Equal(t, http.StatusTooManyRequests, errors.CodeResourceExhausted.HTTPStatus())
+	require.Equal(t, http.StatusInternalServerError, errors.Code(1234).HTTPStatus())
+}
+
+func TestHTTPStatus(t *testing.T) {
+	require.Equal(t, http.StatusOK, errors.HTTPStatus(nil))
+	require.Equal(t, http.StatusInternalServerError, errors.HTTPStatus(errors.New("foo")))
+	require.Equal(t, http.StatusNotFound, errors.HTTPStatus(
+		errors.Wrap(errors.NewCoded(errors.CodeNotFound, "not found"), "get"),
+	))
+	require.Equal(t, http.StatusTeapot, errors.HTTPStatus(
+		errors.Wrap(statusError(http.StatusTeapot), "brew"),
+	))
+}
+
+type statusError int
+
+func (e statusError) Error() string {
+	return http.StatusText(int(e))
+}
+
+func (e statusError) HTTPStatus() int {
+	return int(e)
+}
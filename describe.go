@@ -0,0 +1,259 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A Node is a structured, serializable representation of an error and its
+// causes, as produced by [Describe].
+type Node struct {
+	// Fields are the fields attached to the error (see [WithField]).
+	Fields map[string]any `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Message is the error's message.
+	Message string `json:"message" yaml:"message"`
+	// Type is the error's Go type. If the error was folded with annotating
+	// wrappers, Type is the type of the innermost error.
+	Type string `json:"type" yaml:"type"`
+	// Code is the name of the code carried by the error, if any (see
+	// [Code.String]).
+	Code string `json:"code,omitempty" yaml:"code,omitempty"`
+	// Stack is the stack trace carried by the error, if any (see
+	// [PanicError]).
+	Stack string `json:"stack,omitempty" yaml:"stack,omitempty"`
+	// Caller is the location, formatted as "file:line", recorded by the
+	// error, if any (see [WithCaller]).
+	Caller string `json:"caller,omitempty" yaml:"caller,omitempty"`
+	// Time is the time at which the error occurred, formatted as RFC 3339
+	// with nanoseconds, if recorded (see [WithTimestamp]).
+	Time string `json:"time,omitempty" yaml:"time,omitempty"`
+	// Duration is the time taken by the operation that failed with the
+	// error, if recorded (see [WithDuration]).
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Causes are the errors wrapped by the error. Joined errors have more
+	// than one cause.
+	Causes []*Node `json:"causes,omitempty" yaml:"causes,omitempty"`
+}
+
+// Describe returns a structured representation of err's chain, suitable for
+// serialization, e.g. with encoding/json or [ToYAML]. Wrappers that only
+// annotate an error without changing its message, such as those created by
+// [WithField], [WithCaller], [WithTime], [WithDuration], and [NewCoded], are
+// folded into the error they wrap. The traversal is bounded as described by
+// [SetMaxDepth]; in particular, the causes of an error that wraps multiple
+// errors are only described at its first occurrence in the chain. If err is
+// nil, Describe returns nil.
+func Describe(err error) *Node {
+	return describe(err, newChainGuard())
+}
+
+func describe(err error, guard chainGuard) *Node {
+	if err == nil || !guard.visit(err) {
+		return nil
+	}
+	if lazy, ok := err.(*lazyError); ok {
+		return describe(lazy.get(), guard)
+	}
+
+	var (
+		node   = &Node{Message: err.Error()}
+		fields map[string]any
+	)
+
+	for {
+		describeAnnotations(node, err, &fields)
+
+		next := errors.Unwrap(err)
+		if next == nil || next.Error() != node.Message || !guard.visit(next) {
+			break
+		}
+		err = next
+	}
+	node.Fields = fields
+	node.Type = fmt.Sprintf("%T", err)
+
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		if !guard.expand(err) {
+			break
+		}
+		for _, cause := range x.Unwrap() {
+			if child := describe(cause, guard.branch()); child != nil {
+				node.Causes = append(node.Causes, child)
+			}
+		}
+	default:
+		if child := describe(errors.Unwrap(err), guard); child != nil {
+			node.Causes = []*Node{child}
+		}
+	}
+
+	return node
+}
+
+// ToYAML returns the YAML encoding of err's structured representation, as
+// produced by [Describe]. If err is nil, ToYAML encodes a null value.
+//
+// Nodes are encoded as block mappings, while scalars and field values are
+// encoded as JSON, which is valid YAML, so that this package does not need to
+// depend on a YAML library.
+func ToYAML(err error) ([]byte, error) {
+	node := Describe(err)
+	if node == nil {
+		return []byte("null\n"), nil
+	}
+
+	var b bytes.Buffer
+	if yamlErr := writeYAMLNode(&b, node, ""); yamlErr != nil {
+		return nil, yamlErr
+	}
+	return b.Bytes(), nil
+}
+
+// writeYAMLNode writes node as a block mapping whose keys are indented by
+// indent. The first key is written at the current position, so that node can
+// follow a sequence indicator.
+func writeYAMLNode(b *bytes.Buffer, node *Node, indent string) error {
+	if len(node.Fields) > 0 {
+		b.WriteString("fields:\n")
+		if err := writeYAMLFields(b, node.Fields, indent+"  "); err != nil {
+			return err
+		}
+		b.WriteString(indent)
+	}
+
+	scalars := [...]struct {
+		key   string
+		value string
+	}{
+		{"message", node.Message},
+		{"type", node.Type},
+		{"code", node.Code},
+		{"stack", node.Stack},
+		{"caller", node.Caller},
+		{"time", node.Time},
+		{"duration", node.Duration},
+	}
+	for i, scalar := range scalars {
+		// The message and type are always present, mirroring the JSON
+		// encoding of a Node.
+		if i > 1 && len(scalar.value) == 0 {
+			continue
+		}
+		if i > 0 {
+			b.WriteString(indent)
+		}
+		b.WriteString(scalar.key + ": ")
+		if err := writeYAMLScalar(b, scalar.value); err != nil {
+			return err
+		}
+	}
+
+	if len(node.Causes) > 0 {
+		b.WriteString(indent + "causes:\n")
+		for _, cause := range node.Causes {
+			b.WriteString(indent + "  - ")
+			if err := writeYAMLNode(b, cause, indent+"    "); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeYAMLFields(b *bytes.Buffer, fields map[string]any, indent string) error {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b.WriteString(indent)
+		if err := writeYAMLScalar(b, k); err != nil {
+			return err
+		}
+		b.Truncate(b.Len() - 1)
+		b.WriteString(": ")
+		if err := writeYAMLScalar(b, fields[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAMLScalar writes the JSON encoding of v, followed by a newline.
+func writeYAMLScalar(b *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+func describeAnnotations(node *Node, err error, fields *map[string]any) {
+	if coder, ok := err.(interface{ Code() Code }); ok && len(node.Code) == 0 {
+		node.Code = coder.Code().String()
+	}
+
+	if x, ok := err.(*fieldsError); ok {
+		mergeFields(fields, x.fields)
+	}
+
+	describeMetadata(node, err)
+}
+
+func describeMetadata(node *Node, err error) {
+	switch x := err.(type) {
+	case *PanicError:
+		if len(node.Stack) == 0 {
+			node.Stack = string(x.Stack)
+		}
+	case *callerError:
+		if len(node.Caller) == 0 {
+			node.Caller = x.location()
+		}
+	case *timeError:
+		if len(node.Time) == 0 {
+			node.Time = x.time.Format(time.RFC3339Nano)
+		}
+	case *durationError:
+		if len(node.Duration) == 0 {
+			node.Duration = x.duration.String()
+		}
+	}
+}
+
+func mergeFields(fields *map[string]any, add map[string]any) {
+	if *fields == nil {
+		*fields = make(map[string]any, len(add))
+	}
+	for k, v := range add {
+		if _, exists := (*fields)[k]; !exists {
+			(*fields)[k] = v
+		}
+	}
+}
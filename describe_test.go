@@ -0,0 +1,149 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestDescribe(t *testing.T) {
+	var (
+		notFound = errors.WithField(
+			errors.NewCoded(errors.CodeNotFound, "not found"),
+			"user_id",
+			42,
+		)
+		err = errors.Join(
+			errors.Wrap(notFound, "get user"),
+			errors.Lazy(func() error { return testError("lazy") }),
+			errors.Lazy(func() error { return nil }),
+		)
+	)
+
+	require.Equal(t, &errors.Node{
+		Message: "get user: not found\nlazy",
+		Type:    "*errors.joinError",
+		Causes: []*errors.Node{
+			{
+				Message: "get user: not found",
+				Type:    "*errors.wrapError",
+				Causes: []*errors.Node{
+					{
+						Message: "not found",
+						Type:    "*errors.codedError",
+						Code:    "not_found",
+						Fields:  map[string]any{"user_id": 42},
+					},
+				},
+			},
+			{
+				Message: "lazy",
+				Type:    "errors_test.testError",
+			},
+		},
+	}, errors.Describe(err))
+
+	require.Nil(t, errors.Describe(nil))
+	require.Nil(t, errors.Describe(errors.Lazy(func() error { return nil })))
+}
+
+func TestDescribe_JSON(t *testing.T) {
+	err := errors.Wrap(errors.NewCoded(errors.CodeAborted, "aborted"), "commit")
+
+	raw, jsonErr := json.Marshal(errors.Describe(err))
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{
+		"message": "commit: aborted",
+		"type": "*errors.wrapError",
+		"causes": [
+			{"message": "aborted", "type": "*errors.codedError", "code": "aborted"}
+		]
+	}`, string(raw))
+}
+
+func TestDescribe_Stack(t *testing.T) {
+	err := errors.Recover("boom")
+
+	var panicErr *errors.PanicError
+	require.True(t, errors.As(err, &panicErr))
+
+	node := errors.Describe(errors.Wrap(err, "handle"))
+	require.Len(t, node.Causes, 1)
+	require.Equal(t, "panic: boom", node.Causes[0].Message)
+	require.Equal(t, string(panicErr.Stack), node.Causes[0].Stack)
+}
+
+func TestToYAML(t *testing.T) {
+	err := errors.Wrap(
+		errors.WithField(errors.NewCoded(errors.CodeAborted, "aborted"), "attempt", 3),
+		"commit",
+	)
+
+	raw, yamlErr := errors.ToYAML(err)
+	require.NoError(t, yamlErr)
+	require.YAMLEq(t, `
+message: "commit: aborted"
+type: "*errors.wrapError"
+causes:
+  - message: aborted
+    type: "*errors.codedError"
+    code: aborted
+    fields:
+      attempt: 3
+`, string(raw))
+
+	raw, yamlErr = errors.ToYAML(nil)
+	require.NoError(t, yamlErr)
+	require.Equal(t, "null\n", string(raw))
+}
+
+func TestToYAML_Escaping(t *testing.T) {
+	err := errors.Join(
+		errors.WithFields(errors.New("key: \"value\"\n# not a comment"), map[string]any{
+			"nested":   map[string]any{"ids": []int{1, 2}},
+			"odd: key": "- item",
+		}),
+		errors.New("second"),
+	)
+
+	raw, yamlErr := errors.ToYAML(err)
+	require.NoError(t, yamlErr)
+	require.YAMLEq(t, `
+message: "key: \"value\"\n# not a comment\nsecond"
+type: "*errors.joinError"
+causes:
+  - message: "key: \"value\"\n# not a comment"
+    type: "*errors.errorString"
+    fields:
+      nested:
+        ids: [1, 2]
+      "odd: key": "- item"
+  - message: second
+    type: "*errors.errorString"
+`, string(raw))
+
+	_, yamlErr = errors.ToYAML(errors.WithField(errors.New("foo"), "bad", func() {}))
+	require.Error(t, yamlErr)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import "regexp"
+
+var (
+	_uuidPattern = regexp.MustCompile(
+		`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`,
+	)
+	_addrPattern = regexp.MustCompile(
+		`(?i)(\b\d{1,3}(\.\d{1,3}){3}|\[[0-9a-f:.%]+\])(:\d+)?\b`,
+	)
+	_durationPattern = regexp.MustCompile(`\b(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+\b`)
+	_hexPattern      = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`)
+	_numberPattern   = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+// Equivalent reports whether a and b are structurally the same error, i.e.
+// whether their chains (as described by [Describe]) have the same shape, the
+// same types and codes, and the same messages once dynamic parts have been
+// normalized as described by [Canonical], so that e.g. "timeout after 1.2s"
+// and "timeout after 1.3s" are equivalent. Fields and stacks are not
+// compared.
+func Equivalent(a error, b error) bool {
+	return equivalentNodes(Describe(a), Describe(b))
+}
+
+func equivalentNodes(a *Node, b *Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a.Type != b.Type ||
+		a.Code != b.Code ||
+		len(a.Causes) != len(b.Causes) ||
+		normalizeMessage(a.Message) != normalizeMessage(b.Message) {
+		return false
+	}
+
+	for i := range a.Causes {
+		if !equivalentNodes(a.Causes[i], b.Causes[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Canonical returns err's message with volatile tokens replaced by
+// placeholders, e.g. so that errors that differ only in such tokens can be
+// grouped or fingerprinted together. UUIDs are replaced with "<uuid>", IP
+// addresses (with or without ports) with "<addr>", durations such as "1.5s"
+// with "<duration>", hexadecimal literals with "<hex>", and any other numbers
+// with "<n>". For example, "dial tcp 10.0.0.7:443: i/o timeout after 30s"
+// becomes "dial tcp <addr>: i/o timeout after <duration>". Because err's
+// message includes the messages of the errors it wraps, tokens are replaced
+// across the whole chain. If err is nil, Canonical returns an empty string.
+func Canonical(err error) string {
+	if LazyOrNil(err) == nil {
+		return ""
+	}
+	return normalizeMessage(err.Error())
+}
+
+func normalizeMessage(msg string) string {
+	msg = _uuidPattern.ReplaceAllLiteralString(msg, "<uuid>")
+	msg = _addrPattern.ReplaceAllLiteralString(msg, "<addr>")
+	msg = _durationPattern.ReplaceAllLiteralString(msg, "<duration>")
+	msg = _hexPattern.ReplaceAllLiteralString(msg, "<hex>")
+	return _numberPattern.ReplaceAllLiteralString(msg, "<n>")
+}
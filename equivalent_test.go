@@ -0,0 +1,149 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestEquivalent(t *testing.T) {
+	cases := map[string]struct {
+		giveA error
+		giveB error
+		want  bool
+	}{
+		"nil": {
+			giveA: nil,
+			giveB: nil,
+			want:  true,
+		},
+		"one nil": {
+			giveA: errors.New("foo"),
+			giveB: nil,
+			want:  false,
+		},
+		"numbers": {
+			giveA: errors.Newf("timeout after %.1fs", 1.2),
+			giveB: errors.Newf("timeout after %.1fs", 1.3),
+			want:  true,
+		},
+		"uuids": {
+			giveA: errors.New("user 0f8fad5b-d9cb-469f-a165-70867728950e not found"),
+			giveB: errors.New("user 7c9e6679-7425-40de-944b-e07fc1f90ae7 not found"),
+			want:  true,
+		},
+		"hex": {
+			giveA: errors.New("bad pointer 0xc000012345"),
+			giveB: errors.New("bad pointer 0xc0000abcde"),
+			want:  true,
+		},
+		"different messages": {
+			giveA: errors.New("timeout after 1s"),
+			giveB: errors.New("canceled after 1s"),
+			want:  false,
+		},
+		"different codes": {
+			giveA: errors.NewCoded(errors.CodeNotFound, "user 1"),
+			giveB: errors.NewCoded(errors.CodeInternal, "user 2"),
+			want:  false,
+		},
+		"different types": {
+			giveA: errors.NewCoded(errors.CodeNotFound, "user 1"),
+			giveB: errors.New("user 2"),
+			want:  false,
+		},
+		"wrapped": {
+			giveA: errors.Wrap(errors.NewCoded(errors.CodeNotFound, "user 1"), "get"),
+			giveB: errors.Wrap(errors.NewCoded(errors.CodeNotFound, "user 2"), "get"),
+			want:  true,
+		},
+		"join shape": {
+			giveA: errors.Join(errors.New("foo 1"), errors.New("bar 2")),
+			giveB: errors.Join(errors.New("foo 3")),
+			want:  false,
+		},
+		"fields ignored": {
+			giveA: errors.WithField(errors.New("foo"), "a", 1),
+			giveB: errors.WithField(errors.New("foo"), "a", 2),
+			want:  true,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.Equivalent(tt.giveA, tt.giveB))
+			require.Equal(t, tt.want, errors.Equivalent(tt.giveB, tt.giveA))
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	cases := map[string]struct {
+		give error
+		want string
+	}{
+		"nil": {
+			give: nil,
+			want: "",
+		},
+		"unchanged": {
+			give: errors.New("not found"),
+			want: "not found",
+		},
+		"uuid": {
+			give: errors.New("user 0f8fad5b-d9cb-469f-a165-70867728950e not found"),
+			want: "user <uuid> not found",
+		},
+		"ipv4": {
+			give: errors.New("dial tcp 10.0.0.7:443: connection refused"),
+			want: "dial tcp <addr>: connection refused",
+		},
+		"ipv6": {
+			give: errors.New("dial tcp [fe80::1]:8080: connection refused"),
+			want: "dial tcp <addr>: connection refused",
+		},
+		"durations": {
+			give: errors.New("timeout after 1m30s (budget 1.5s, waited 250ms)"),
+			want: "timeout after <duration> (budget <duration>, waited <duration>)",
+		},
+		"hex": {
+			give: errors.New("bad pointer 0xc000012345"),
+			want: "bad pointer <hex>",
+		},
+		"numbers": {
+			give: errors.New("read 512 of 1024 bytes"),
+			want: "read <n> of <n> bytes",
+		},
+		"chain": {
+			give: errors.Wrapf(errors.New("attempt 3 failed after 2s"), "dial 10.0.0.1"),
+			want: "dial <addr>: attempt <n> failed after <duration>",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.Canonical(tt.give))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errgroup
+
+// A GoGroup executes functions and waits for them to return, like the Group
+// type of the golang.org/x/sync/errgroup package, which implements GoGroup.
+type GoGroup interface {
+	// Go executes fn in a new goroutine.
+	Go(fn func() error)
+	// Wait blocks until all functions passed to Go have returned, and
+	// returns the first non-nil error that they returned, if any.
+	Wait() error
+}
+
+// An Adapter layers a Group's error handling on top of a GoGroup, so that code
+// that is given a GoGroup, such as a *golang.org/x/sync/errgroup.Group, can
+// use this package's options incrementally (see Adapt).
+type Adapter struct {
+	x GoGroup
+	g *Group
+}
+
+// Adapt returns an Adapter that executes functions using x, and that records
+// their errors as a Group configured with the given options would. Functions
+// are still executed, limited, and canceled by x; options that control how
+// functions are scheduled, i.e. WithInline, WithLimit, WithWorkers,
+// WithMaxWeight, and WithCancelOnError, are ignored. Options that control how
+// functions are executed, such as WithPanicRecovery and WithRetry, and how
+// errors are recorded, such as WithIgnoredErrors and WithErrorTransform,
+// apply.
+func Adapt(x GoGroup, opts ...Option) *Adapter {
+	g := &Group{
+		options: DefaultOptions().With(opts...),
+	}
+	g.options.Inline = false
+	g.options.CancelOnError = false
+	g.options.Workers = 0
+	g.options.MaxWeight = 0
+	g.SetLimit(0)
+
+	return &Adapter{
+		x: x,
+		g: g,
+	}
+}
+
+// Go executes fn using the Adapter's GoGroup. The error returned by fn is
+// filtered and transformed according to the Adapter's options before it is
+// returned to the GoGroup, so that, e.g., ignored errors do not cancel the
+// context of a *golang.org/x/sync/errgroup.Group.
+func (a *Adapter) Go(fn func() error) {
+	t := a.g.newTask(a.g.taskName(fn), fn)
+	a.x.Go(func() error {
+		err := a.g.filterError(a.g.observe(t))
+		if err != nil {
+			a.g.appendFailure(t, err)
+		}
+		return err
+	})
+}
+
+// Wait waits for the Adapter's GoGroup, and returns the errors recorded for
+// the functions passed to Go, combined as Group.Wait would combine them. If
+// no errors were recorded, but the GoGroup returned an error, e.g. from a
+// function passed to it directly, that error is returned instead.
+func (a *Adapter) Wait() error {
+	xerr := a.x.Wait()
+	if err := a.g.Wait(); err != nil {
+		return err
+	}
+	return xerr
+}
+
+// Stats returns counters describing the functions passed to Go, as
+// Group.Stats does.
+func (a *Adapter) Stats() Stats {
+	return a.g.Stats()
+}
@@ -0,0 +1,80 @@
+package errgroup_test
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors/errgroup"
+)
+
+// goGroup is a minimal errgroup.GoGroup, like a
+// *golang.org/x/sync/errgroup.Group.
+type goGroup struct {
+	err  error
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func (g *goGroup) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+			})
+		}
+	}()
+}
+
+func (g *goGroup) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+func TestAdapt(t *testing.T) {
+	var (
+		x = &goGroup{}
+		a = errgroup.Adapt(
+			x,
+			errgroup.WithIgnoredErrors(io.EOF),
+			errgroup.WithOrderedErrors(),
+			errgroup.WithPanicRecovery(),
+		)
+		done = make(chan struct{})
+	)
+
+	a.Go(func() error {
+		<-done
+		return errA
+	})
+	a.Go(func() error { return io.EOF })
+	a.Go(func() error {
+		defer close(done)
+		return errB
+	})
+	a.Go(func() error { return nil })
+
+	require.EqualError(t, a.Wait(), "a\nb")
+	require.Equal(t, errB, x.err)
+	require.Equal(t, errgroup.Stats{
+		Scheduled: 4,
+		Completed: 4,
+		Succeeded: 1,
+		Failed:    2,
+		Ignored:   1,
+	}, a.Stats())
+}
+
+func TestAdapt_Direct(t *testing.T) {
+	var (
+		x = &goGroup{}
+		a = errgroup.Adapt(x)
+	)
+
+	x.Go(func() error { return errC })
+	a.Go(func() error { return nil })
+	require.Equal(t, errC, a.Wait())
+}
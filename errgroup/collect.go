@@ -0,0 +1,46 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errgroup
+
+import "context"
+
+// Collect executes the given functions concurrently with ctx, and returns
+// their results in the order in which the functions were given, along with
+// an error joining all non-nil errors returned by the functions, in the same
+// order. The result of a function that returns an error is included as
+// returned. Functions are not canceled when another function fails; to stop
+// early, cancel ctx.
+func Collect[T any](ctx context.Context, fns ...func(context.Context) (T, error)) ([]T, error) {
+	var (
+		g       = New(WithOrderedErrors())
+		results = make([]T, len(fns))
+	)
+
+	for i, fn := range fns {
+		i, fn := i, fn
+		g.Add(func() (err error) {
+			results[i], err = fn(ctx)
+			return err
+		})
+	}
+
+	return results, g.Wait()
+}
@@ -0,0 +1,44 @@
+package errgroup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors/errgroup"
+)
+
+func TestCollect(t *testing.T) {
+	results, err := errgroup.Collect(
+		context.Background(),
+		func(context.Context) (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			return 1, nil
+		},
+		func(context.Context) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 2, errB
+		},
+		func(context.Context) (int, error) {
+			return 3, errA
+		},
+	)
+	require.Equal(t, []int{1, 2, 3}, results)
+	require.EqualError(t, err, "b\na")
+}
+
+func TestCollect_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := errgroup.Collect(ctx, func(ctx context.Context) (string, error) {
+		return "x", ctx.Err()
+	})
+	require.Equal(t, []string{"x"}, results)
+	require.ErrorIs(t, err, context.Canceled)
+
+	results, err = errgroup.Collect[string](ctx)
+	require.Empty(t, results)
+	require.NoError(t, err)
+}
@@ -23,10 +23,18 @@ package errgroup
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"go.uber.org/multierr"
+	"go.mway.dev/errors"
 )
 
 type (
@@ -37,89 +45,1226 @@ type (
 	ContextErrFunc = func(context.Context) error
 )
 
+// ErrShutdown is the cause of a Group's context after Group.Shutdown is
+// called.
+var ErrShutdown = errors.New("errgroup: shutdown")
+
+// errTaskTimeout is the cause of the contexts of functions that exceed the
+// timeout configured with WithTaskTimeout.
+var errTaskTimeout = errors.New("task timeout exceeded")
+
+// A TaskError associates an error with the name of the function executed by a
+// Group that returned it (see Group.AddNamed and WithTaskNames).
+type TaskError struct {
+	// Err is the error returned by the function.
+	Err error
+	// Name is the name of the function.
+	Name string
+}
+
+// Error returns the task error message.
+func (e *TaskError) Error() string {
+	return "task " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the function.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// Stats are counters describing the functions executed by a Group.
+type Stats struct {
+	// Scheduled is the number of functions submitted to the Group.
+	Scheduled int
+	// Completed is the number of functions that have returned.
+	Completed int
+	// Succeeded is the number of functions that returned a nil error.
+	Succeeded int
+	// Failed is the number of functions that returned a non-nil error that
+	// was not ignored, including errors that were not recorded because of
+	// WithFirstOnly or WithMaxErrors.
+	Failed int
+	// Ignored is the number of functions that returned a non-nil error that
+	// was ignored (see WithIgnoredErrors and WithIgnoreFunc) or discarded by
+	// an error transform (see WithErrorTransform).
+	Ignored int
+	// TotalDuration is the sum of the durations of the functions that have
+	// returned. It is only tracked if the Group was configured using the
+	// WithTaskTiming() option.
+	TotalDuration time.Duration
+	// MaxDuration is the duration of the slowest function that has
+	// returned. It is only tracked if the Group was configured using the
+	// WithTaskTiming() option.
+	MaxDuration time.Duration
+}
+
+type groupStats struct {
+	scheduled     atomic.Int64
+	completed     atomic.Int64
+	succeeded     atomic.Int64
+	failed        atomic.Int64
+	ignored       atomic.Int64
+	totalDuration atomic.Int64
+	maxDuration   atomic.Int64
+}
+
+func (s *groupStats) reset() {
+	s.scheduled.Store(0)
+	s.completed.Store(0)
+	s.succeeded.Store(0)
+	s.failed.Store(0)
+	s.ignored.Store(0)
+	s.totalDuration.Store(0)
+	s.maxDuration.Store(0)
+}
+
+func (s *groupStats) load() Stats {
+	return Stats{
+		Scheduled:     int(s.scheduled.Load()),
+		Completed:     int(s.completed.Load()),
+		Succeeded:     int(s.succeeded.Load()),
+		Failed:        int(s.failed.Load()),
+		Ignored:       int(s.ignored.Load()),
+		TotalDuration: time.Duration(s.totalDuration.Load()),
+		MaxDuration:   time.Duration(s.maxDuration.Load()),
+	}
+}
+
+func (s *groupStats) observe(elapsed time.Duration) {
+	s.totalDuration.Add(int64(elapsed))
+	for {
+		prev := s.maxDuration.Load()
+		if int64(elapsed) <= prev || s.maxDuration.CompareAndSwap(prev, int64(elapsed)) {
+			return
+		}
+	}
+}
+
+// A TimedError associates an error with the execution time of the function
+// executed by a Group that returned it (see WithTaskTiming).
+type TimedError struct {
+	// Start is the time at which the function started executing.
+	Start time.Time
+	// Err is the error returned by the function.
+	Err error
+	// Elapsed is the duration of the function.
+	Elapsed time.Duration
+}
+
+// Error returns the timed error message.
+func (e *TimedError) Error() string {
+	return "task failed after " + e.Elapsed.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the function.
+func (e *TimedError) Unwrap() error {
+	return e.Err
+}
+
+// A TruncatedError records that a Group omitted errors because it had already
+// recorded the maximum number of errors configured with WithMaxErrors.
+type TruncatedError struct {
+	// Omitted is the number of errors that were omitted.
+	Omitted int
+}
+
+// Error returns the truncation message.
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("%d more error(s) omitted", e.Omitted)
+}
+
+// A SampledError records that a Group left errors out of its recorded errors
+// because they were not sampled (see WithSampling).
+type SampledError struct {
+	// Unsampled is the number of errors that were not sampled.
+	Unsampled int
+}
+
+// Error returns the sampling message.
+func (e *SampledError) Error() string {
+	return fmt.Sprintf("%d error(s) not sampled", e.Unsampled)
+}
+
+// A TaskTimeoutError records that a function executed by a Group exceeded the
+// timeout configured with WithTaskTimeout.
+type TaskTimeoutError struct {
+	// Err is the error returned by the function after its context expired,
+	// typically context.DeadlineExceeded.
+	Err error
+	// Task is the name of the function, as reported by the runtime.
+	Task string
+	// Timeout is the timeout that the function exceeded.
+	Timeout time.Duration
+}
+
+// Error returns the timeout message.
+func (e *TaskTimeoutError) Error() string {
+	return fmt.Sprintf("task %s timed out after %s: %v", e.Task, e.Timeout, e.Err)
+}
+
+// Unwrap returns the error returned by the function.
+func (e *TaskTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// A ShutdownError records that functions executed by a Group did not return
+// before the context given to Group.Shutdown was done.
+type ShutdownError struct {
+	// Err is the cause of the context's cancellation, typically
+	// context.DeadlineExceeded.
+	Err error
+	// Remaining is the number of functions that did not return.
+	Remaining int
+}
+
+// Error returns the shutdown message.
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("%d task(s) did not exit: %v", e.Remaining, e.Err)
+}
+
+// Unwrap returns the cause of the context's cancellation.
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// An Observer is notified as a Group executes functions, e.g. to record
+// tracing spans or metrics for every function uniformly (see WithObserver).
+// An Observer's methods are called from the goroutine executing the function,
+// and must be safe for concurrent use.
+type Observer interface {
+	// TaskStarted is called before the function is executed.
+	TaskStarted(info TaskInfo)
+	// TaskFinished is called after the function returns, with its
+	// duration and the error it returned, before the error is recorded.
+	TaskFinished(info TaskInfo, elapsed time.Duration, err error)
+}
+
+// A TaskInfo describes a function executed by a Group.
+type TaskInfo struct {
+	// Name is the name of the function, if any (see Group.AddNamed and
+	// WithTaskNames).
+	Name string
+	// Seq is the 1-based order in which the function was submitted to the
+	// Group.
+	Seq uint64
+}
+
 // Group is functionally similar to the standard library's x/sync/errgroup
 // package, but offers more options to customize its behavior based on a given
 // workflow. See the Options documentation for more information.
 //
-// Groups cannot be reused. A zero-value Group is valid and ready to use.
+// Groups cannot be reused unless Reset is called after Wait returns. A
+// zero-value Group is valid and ready to use.
 type Group struct {
-	err     error
-	options Options
-	mu      sync.Mutex
-	wg      sync.WaitGroup
+	parent     context.Context
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	sem        chan struct{}
+	queue      chan task
+	slots      chan struct{}
+	weights    *weightedSem
+	release    func(error)
+	stream     *errStream
+	done       chan struct{}
+	errs       []error
+	seqs       []uint64
+	pending    atomic.Pointer[errNode]
+	options    Options
+	seq        atomic.Uint64
+	numErrs    atomic.Int64
+	omitted    atomic.Int64
+	sampledOut atomic.Int64
+	subgroups  atomic.Int64
+	stats      groupStats
+	mu         sync.Mutex
+	wg         sync.WaitGroup
+	waited     bool
+	streaming  atomic.Bool
 }
 
 // New creates a new Group with the given options.
 func New(opts ...Option) *Group {
-	options := DefaultOptions().With(opts...)
-	return &Group{
-		options: options,
+	g := &Group{
+		options: DefaultOptions().With(opts...),
+	}
+	if g.options.CancelOnError || g.options.StopOnFatal {
+		g.parent = context.Background()
+		g.ctx, g.cancel = context.WithCancelCause(g.parent)
+	}
+	g.SetLimit(g.options.Limit)
+	return g
+}
+
+// WithContext creates a new Group with the given options, along with a context
+// derived from ctx. The derived context is canceled the first time a function
+// executed by the Group returns a non-nil error that is not ignored (see
+// WithIgnoredErrors), or the first time Wait returns, whichever occurs first;
+// its cause (see context.Cause) is the error that canceled it. Functions
+// passed to AddContext receive the derived context. WithContext implies
+// WithCancelOnError.
+func WithContext(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	g := &Group{
+		options: DefaultOptions().With(opts...),
+	}
+	g.options.CancelOnError = true
+	g.parent = ctx
+	g.ctx, g.cancel = context.WithCancelCause(ctx)
+	g.SetLimit(g.options.Limit)
+	return g, g.ctx
+}
+
+// newGroup creates a new Group with the given options whose functions receive
+// ctx, or a context derived from ctx if the Group cancels on error.
+func newGroup(ctx context.Context, opts []Option) *Group {
+	g := &Group{
+		options: DefaultOptions().With(opts...),
+		parent:  ctx,
+		ctx:     ctx,
+	}
+	if g.options.CancelOnError || g.options.StopOnFatal {
+		g.ctx, g.cancel = context.WithCancelCause(ctx)
+	}
+	g.SetLimit(g.options.Limit)
+	return g
+}
+
+// Subgroup creates a new Group with the given options that is nested within
+// g. Functions passed to the subgroup's AddContext receive a context derived
+// from g's context, so canceling g's context cancels them too. g's Wait blocks
+// until the subgroup's Wait returns, at which point the subgroup's error, if
+// any, is recorded by g as if returned by one of its functions. The subgroup
+// itself is not counted in g's Stats; its functions are counted in its own.
+func (g *Group) Subgroup(opts ...Option) *Group {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		sub = newGroup(ctx, opts)
+		t   = task{seq: g.seq.Add(1)}
+	)
+	g.subgroups.Add(1)
+	g.wg.Add(1)
+	sub.release = func(err error) {
+		if err != nil {
+			if err = g.transformError(err); err != nil {
+				g.reportFailure(t, err)
+			}
+		}
+		g.subgroups.Add(-1)
+		g.wg.Done()
+	}
+	return sub
+}
+
+// Reset prepares the Group to be reused, discarding its recorded errors and
+// resetting its Stats, while keeping its options. If the Group has a context
+// (see WithContext, WithCancelOnError, and WithStopOnFatal), a new context is derived from the
+// Group's original parent context and is passed to functions given to
+// AddContext from then on.
+//
+// Reset must not be called while any functions passed to the Group or any of
+// its subgroups are still executing, e.g. before Wait has returned.
+func (g *Group) Reset() {
+	stats := g.stats.load()
+	if active := stats.Scheduled - stats.Completed + int(g.subgroups.Load()); active != 0 {
+		panic(fmt.Errorf(
+			"errgroup: reset while %d functions in the group are still active",
+			active,
+		))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	clear(g.errs)
+	g.errs = g.errs[:0]
+	g.pending.Store(nil)
+	g.stream = nil
+	g.streaming.Store(false)
+	g.done = nil
+	g.waited = false
+	g.seqs = g.seqs[:0]
+	g.omitted.Store(0)
+	g.sampledOut.Store(0)
+	g.numErrs.Store(0)
+	g.seq.Store(0)
+	g.stats.reset()
+
+	if g.cancel != nil {
+		g.cancel(context.Canceled)
+		g.ctx, g.cancel = context.WithCancelCause(g.parent)
+	}
+}
+
+// SetLimit limits the number of functions that the Group executes
+// concurrently to at most n; once the limit is reached, Add blocks until a
+// running function returns. If n is not positive, the number of concurrently
+// executing functions is unbounded.
+//
+// SetLimit must not be called while any functions passed to the Group are
+// still executing.
+func (g *Group) SetLimit(n int) {
+	if active := len(g.sem); active != 0 {
+		panic(fmt.Errorf(
+			"errgroup: modify limit while %d functions in the group are still active",
+			active,
+		))
+	}
+
+	g.options.Limit = n
+	if n <= 0 {
+		g.sem = nil
+		return
 	}
+	g.sem = make(chan struct{}, n)
 }
 
 // Add executes the provided functions and stores returned errors for retrieval
 // with Wait(). If the Group was configured using the WithInline() option, the
 // given functions are executed immediately and serially in the calling
-// goroutine; otherwise, the given functions are executed in parallel.
+// goroutine; otherwise, the given functions are executed in parallel. If the
+// Group has a concurrency limit (see WithLimit and SetLimit), Add blocks
+// while the limit is reached; if the Group executes functions using workers
+// (see WithWorkers), Add blocks while the workers' queue is full.
 func (g *Group) Add(fns ...ErrFunc) {
-	if g.options.Inline {
-		for _, f := range fns {
-			g.appendError(f())
+	for _, fn := range fns {
+		g.run(g.newTask(g.taskName(fn), fn))
+	}
+}
+
+// AddNamed executes fn as Add does, wrapping any error it returns in a
+// *TaskError with the given name, e.g. "task <name>: <error>".
+func (g *Group) AddNamed(name string, fn ErrFunc) {
+	g.run(g.newTask(name, fn))
+}
+
+// AddFrom executes the functions received from fns as Add does, until fns is
+// closed or ctx is done, whichever occurs first. AddFrom blocks while
+// receiving functions, and applies the Group's concurrency limit (see
+// WithLimit and WithWorkers) as backpressure to the sender. If ctx is done
+// before fns is closed, AddFrom returns the cause of ctx's cancellation (see
+// context.Cause); functions already received continue to execute. Errors
+// returned by the functions are retrieved with Wait, as for Add.
+func (g *Group) AddFrom(ctx context.Context, fns <-chan ErrFunc) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case fn, ok := <-fns:
+			if !ok {
+				return nil
+			}
+			g.run(g.newTask(g.taskName(fn), fn))
 		}
+	}
+}
+
+// Detach executes fn in a new goroutine without tracking it, e.g. for
+// best-effort work launched alongside the Group's functions. Wait does not
+// wait for fn, fn does not count towards the Group's limits or Stats, and its
+// error neither cancels the Group's context nor is returned by Wait. Instead,
+// if fn returns an error that is not ignored or discarded, the error is
+// passed to the Group's OnError function, if any (see WithOnError). Options
+// that affect how errors and panics are handled, such as WithPanicRecovery and
+// WithTaskNames, apply to fn, but fn is never retried (see WithRetry), since
+// retries are bound to the Group's context, which may be replaced by Reset
+// while fn is still executing.
+func (g *Group) Detach(fn ErrFunc) {
+	t := task{
+		fn:   fn,
+		name: g.taskName(fn),
+	}
+
+	go func() {
+		err := g.call(t, false)
+		if err == nil {
+			return
+		}
+
+		if err = g.transformError(err); err != nil && g.options.OnError != nil {
+			g.options.OnError(err)
+		}
+	}()
+}
+
+// AddWeighted executes fn as Add does, once weight can be acquired from the
+// Group's weight budget (see WithMaxWeight) without exceeding it; the weight
+// is released when fn returns. Functions are admitted in the order in which
+// they are given, so AddWeighted blocks while the budget is exhausted or while
+// earlier functions are waiting for their weight. If the Group has no weight
+// budget, weight is ignored. AddWeighted panics if weight is negative or
+// exceeds the Group's budget.
+func (g *Group) AddWeighted(weight int64, fn ErrFunc) {
+	name := g.taskName(fn)
+	if g.options.MaxWeight <= 0 || g.options.Inline {
+		g.run(g.newTask(name, fn))
 		return
 	}
 
-	for _, f := range fns {
-		f := f
-		g.wg.Add(1)
-		go func() {
-			defer g.wg.Done()
-			g.appendError(f())
-		}()
+	if weight < 0 || weight > g.options.MaxWeight {
+		panic(fmt.Errorf(
+			"errgroup: weight %d is outside the group's budget of %d",
+			weight,
+			g.options.MaxWeight,
+		))
+	}
+
+	weights := g.weightedSem()
+	weights.acquire(weight)
+	g.run(g.newTask(name, func() error {
+		defer weights.release(weight)
+		return fn()
+	}))
+}
+
+func (g *Group) weightedSem() *weightedSem {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.weights == nil {
+		g.weights = newWeightedSem(g.options.MaxWeight)
+	}
+	return g.weights
+}
+
+// Go executes fn as Add does. Go is provided for compatibility with
+// golang.org/x/sync/errgroup.
+func (g *Group) Go(fn func() error) {
+	g.run(g.newTask(g.taskName(fn), fn))
+}
+
+// TryGo executes fn as TryAdd does. TryGo is provided for compatibility with
+// golang.org/x/sync/errgroup.
+func (g *Group) TryGo(fn func() error) bool {
+	return g.TryAdd(fn)
+}
+
+// TryAdd executes fn as Add does, unless doing so would block because the
+// Group has reached its concurrency limit (see WithLimit and SetLimit), in
+// which case fn is not executed. TryAdd reports whether fn was executed.
+func (g *Group) TryAdd(fn ErrFunc) bool {
+	if g.options.Workers > 0 && !g.options.Inline {
+		return g.tryEnqueue(fn)
+	}
+
+	if g.options.Inline || g.sem == nil {
+		g.run(g.newTask(g.taskName(fn), fn))
+		return true
+	}
+
+	select {
+	case g.sem <- struct{}{}:
+		g.launch(g.newTask(g.taskName(fn), fn))
+		return true
+	default:
+		return false
+	}
+}
+
+// AddContext is like Add, but executes functions that accept a context. If
+// the Group has a context, i.e. if it was created with WithContext or
+// Group.Subgroup or configured using the WithCancelOnError() or
+// WithStopOnFatal() option, the functions receive the Group's context, which
+// is canceled as described by WithContext or WithStopOnFatal; otherwise, they
+// receive context.Background(). If the Group was configured using the
+// WithTaskTimeout() option, each function's context has its own
+// deadline, and a function that returns an error after exceeding it is
+// recorded as a *TaskTimeoutError.
+func (g *Group) AddContext(fns ...ContextErrFunc) {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for _, fn := range fns {
+		fn := fn
+		name := g.taskName(fn)
+		if g.options.TaskTimeout > 0 {
+			g.run(g.newTask(name, func() error {
+				return g.callWithTimeout(ctx, fn)
+			}))
+			continue
+		}
+
+		g.run(g.newTask(name, func() error {
+			return fn(ctx)
+		}))
+	}
+}
+
+func (g *Group) callWithTimeout(ctx context.Context, fn ContextErrFunc) error {
+	timeout := g.options.TaskTimeout
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errTaskTimeout)
+	defer cancel()
+
+	err := fn(ctx)
+	if err == nil || context.Cause(ctx) != errTaskTimeout {
+		return err
+	}
+
+	return &TaskTimeoutError{
+		Err:     err,
+		Task:    funcName(fn),
+		Timeout: timeout,
 	}
 }
 
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
+
 // Wait blocks until all functions passed to Add have been executed and
 // returns an error if any were encountered.
 //
 // The error return depends upon whether the Group was configured using the
 // WithFirstOnly() option. If WithFirstOnly was not used, the returned error
-// is an error containing a chain of all non-nil errors returned by the
-// executed functions; if WithFirstOnly was used, the returned error is the
-// first non-nil error returned verbatim by the first function to finish
-// executing.
+// is an error joining all non-nil errors returned by the executed functions,
+// as combined by the Group's join function (see WithJoin); if WithFirstOnly
+// was used, the returned error is the first non-nil error returned verbatim by
+// the first function to finish executing. If only one non-nil error was
+// returned, it is returned verbatim. If the Group was configured using the
+// WithMaxErrors() option and errors were omitted, a *TruncatedError is
+// joined with the recorded errors.
+//
+// If the Group was created with WithContext or configured using the
+// WithCancelOnError() or WithStopOnFatal() option, Wait cancels the Group's
+// context before returning.
 func (g *Group) Wait() error {
 	g.wg.Wait()
 
+	g.mu.Lock()
+	g.stopWorkers(g.queue)
+	release := g.finish()
+	err := errors.Wrap(g.err(), g.options.WrapMessage)
+	g.mu.Unlock()
+
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+	if release != nil {
+		release(err)
+	}
+	return err
+}
+
+// Errors returns a channel that receives each error recorded by the Group, in
+// the order they are recorded, including any errors recorded before Errors was
+// first called. The channel is closed once Wait (or WaitContext or
+// WaitTimeout) returns and all recorded errors have been received. Recording
+// errors never blocks on the channel's receiver; undelivered errors are
+// queued, so the channel should be drained.
+func (g *Group) Errors() <-chan error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	return g.err
+	if g.stream == nil {
+		g.streaming.Store(true)
+		g.collect()
+		g.stream = newErrStream(g.errs)
+		if g.waited {
+			g.stream.close()
+		}
+	}
+	return g.stream.ch
 }
 
-func (g *Group) appendError(err error) {
-	if err == nil {
+// Done returns a channel that is closed once all functions passed to the Group
+// have returned, so that callers can select on the Group's completion, e.g.
+// alongside timers or shutdown signals, and then call Wait, which returns
+// without blocking, to retrieve its error. As with Wait, Done should be called
+// once all functions have been passed to the Group; if no functions are
+// executing when Done is first called, the channel is closed immediately.
+func (g *Group) Done() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.done == nil {
+		done := make(chan struct{})
+		go func() {
+			g.wg.Wait()
+			close(done)
+		}()
+		g.done = done
+	}
+	return g.done
+}
+
+// finish marks the Group as waited on, returning the function that records
+// the Group's error into its parent, if the Group is a subgroup that has not
+// yet done so. g.mu must be held.
+func (g *Group) finish() func(error) {
+	g.collect()
+	g.waited = true
+	if g.stream != nil {
+		g.stream.close()
+	}
+
+	release := g.release
+	g.release = nil
+	return release
+}
+
+// WaitContext is like Wait, but stops waiting once ctx is done. If ctx is done
+// before all functions have been executed, WaitContext returns the errors
+// encountered so far, combined as Wait would combine them, joined with the
+// cause of ctx's cancellation (see context.Cause). Functions that are still
+// executing are not stopped, although the Group's context, if any, is
+// canceled; their errors are not reported.
+func (g *Group) WaitContext(ctx context.Context) error {
+	err, _ := g.wait(ctx, false) //nolint:errcheck
+	return err
+}
+
+// WaitTimeout is like WaitContext, but stops waiting once d has elapsed.
+// WaitTimeout reports whether all functions were executed before the timeout
+// elapsed.
+func (g *Group) WaitTimeout(d time.Duration) (error, bool) { //nolint:revive
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return g.wait(ctx, false)
+}
+
+// Shutdown cancels the Group's context, if any (see WithContext and
+// WithCancelOnError), with ErrShutdown as its cause, and waits for all
+// functions to return or for ctx to be done, whichever occurs first. If all
+// functions return, Shutdown returns their errors as Wait does; otherwise,
+// Shutdown returns the errors encountered so far joined with a
+// *ShutdownError reporting the number of functions that did not return.
+// Functions that have not returned are abandoned; their errors are not
+// reported.
+func (g *Group) Shutdown(ctx context.Context) error {
+	if g.cancel != nil {
+		g.cancel(ErrShutdown)
+	}
+	err, _ := g.wait(ctx, true) //nolint:errcheck
+	return err
+}
+
+// wait waits for all functions to return or for ctx to be done. If ctx is
+// done first, the errors encountered so far are joined with ctx's cause, or
+// with a *ShutdownError if shutdown is true.
+func (g *Group) wait(ctx context.Context, shutdown bool) (error, bool) { //nolint:revive
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return g.Wait(), true
+	case <-ctx.Done():
+	}
+
+	g.mu.Lock()
+	release := g.finish()
+	errs := g.recorded(1)
+	if queue := g.queue; queue != nil {
+		go func() {
+			<-done
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.stopWorkers(queue)
+		}()
+	}
+	g.mu.Unlock()
+
+	cause := context.Cause(ctx)
+	if shutdown {
+		stats := g.stats.load()
+		cause = &ShutdownError{
+			Err:       cause,
+			Remaining: stats.Scheduled - stats.Completed,
+		}
+	}
+
+	err := errors.Wrap(g.join(append(errs, cause)), g.options.WrapMessage)
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+	if release != nil {
+		release(err)
+	}
+	return err, false
+}
+
+func (g *Group) err() error {
+	if g.omitted.Load() == 0 && g.sampledOut.Load() == 0 && !g.options.OrderedErrors {
+		return g.join(g.errs)
+	}
+	return g.join(g.recorded(0))
+}
+
+// recorded returns a copy of the errors recorded by the Group, in submission
+// order if the Group was configured using the WithOrderedErrors() option,
+// followed by a *TruncatedError if any errors were omitted and a
+// *SampledError if any errors were not sampled, with spare capacity for extra
+// additional errors. g.mu must be held.
+func (g *Group) recorded(extra int) []error {
+	errs := make([]error, 0, len(g.errs)+extra+2)
+	if g.options.OrderedErrors {
+		idx := make([]int, len(g.errs))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.Slice(idx, func(i, j int) bool {
+			return g.seqs[idx[i]] < g.seqs[idx[j]]
+		})
+		for _, i := range idx {
+			errs = append(errs, g.errs[i])
+		}
+	} else {
+		errs = append(errs, g.errs...)
+	}
+
+	if omitted := int(g.omitted.Load()); omitted > 0 {
+		errs = append(errs, &TruncatedError{
+			Omitted: omitted,
+		})
+	}
+	if unsampled := int(g.sampledOut.Load()); unsampled > 0 {
+		errs = append(errs, &SampledError{
+			Unsampled: unsampled,
+		})
+	}
+	return errs
+}
+
+func (g *Group) join(errs []error) error {
+	switch {
+	case len(errs) == 0:
+		return nil
+	case len(errs) == 1:
+		return errs[0]
+	case g.options.Join != nil:
+		// errs may alias g.errs, which is reused by Reset, and fn may retain
+		// its argument.
+		return g.options.Join(slices.Clone(errs)...)
+	default:
+		return errors.Join(errs...)
+	}
+}
+
+// A task is a function submitted to a Group.
+type task struct {
+	fn   ErrFunc
+	name string
+	seq  uint64
+}
+
+// newTask returns a new task for fn, numbered in submission order.
+func (g *Group) newTask(name string, fn ErrFunc) task {
+	g.stats.scheduled.Add(1)
+	return task{
+		fn:   fn,
+		name: name,
+		seq:  g.seq.Add(1),
+	}
+}
+
+// Stats returns counters describing the functions executed by the Group so
+// far. Stats may be called at any time, including while functions are still
+// executing and after Wait has returned.
+func (g *Group) Stats() Stats {
+	return g.stats.load()
+}
+
+func (g *Group) run(t task) {
+	if g.options.Inline {
+		g.execute(t)
+		return
+	}
+
+	if g.options.Workers > 0 {
+		queue, slots := g.workerQueue()
+		slots <- struct{}{}
+		g.wg.Add(1)
+		queue <- t
 		return
 	}
 
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.launch(t)
+}
+
+// workerQueue returns the queue consumed by the Group's workers, starting the
+// workers if they are not already running, along with the slots that bound
+// the queue: a slot must be acquired before sending to the queue, and is
+// released by the worker that receives the task, so that sends to the queue
+// never block.
+func (g *Group) workerQueue() (chan task, chan struct{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.queue == nil {
+		g.queue = make(chan task, g.options.Workers)
+		g.slots = make(chan struct{}, g.options.Workers)
+		for i := 0; i < g.options.Workers; i++ {
+			go g.work(g.queue, g.slots)
+		}
+	}
+	return g.queue, g.slots
+}
+
+// tryEnqueue enqueues fn for the Group's workers if a slot is available,
+// reporting whether it did. fn is only numbered once it is certain to be
+// enqueued.
+func (g *Group) tryEnqueue(fn ErrFunc) bool {
+	queue, slots := g.workerQueue()
+	select {
+	case slots <- struct{}{}:
+	default:
+		return false
+	}
+
+	g.wg.Add(1)
+	queue <- g.newTask(g.taskName(fn), fn)
+	return true
+}
+
+func (g *Group) work(queue <-chan task, slots <-chan struct{}) {
+	for t := range queue {
+		<-slots
+		g.execute(t)
+		g.wg.Done()
+	}
+}
+
+// stopWorkers stops the workers consuming from queue, if queue is still the
+// Group's worker queue. g.mu must be held, and no functions may be pending.
+func (g *Group) stopWorkers(queue chan task) {
+	if queue != nil && g.queue == queue {
+		close(queue)
+		g.queue = nil
+		g.slots = nil
+	}
+}
+
+// launch executes t in a new goroutine. If the Group has a concurrency limit,
+// the caller must have already acquired a slot in g.sem.
+func (g *Group) launch(t task) {
+	sem := g.sem
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if sem != nil {
+				<-sem
+			}
+			g.wg.Done()
+		}()
+		g.execute(t)
+	}()
+}
+
+// execute calls t and records its error.
+func (g *Group) execute(t task) {
+	g.appendError(t, g.observe(t))
+}
+
+// observe calls t, notifying the Group's observers and timing t if needed.
+func (g *Group) observe(t task) error {
+	if len(g.options.Observers) == 0 && !g.options.TaskTiming {
+		return g.call(t, true)
+	}
+
+	info := TaskInfo{
+		Name: t.name,
+		Seq:  t.seq,
+	}
+	for _, o := range g.options.Observers {
+		o.TaskStarted(info)
+	}
+
+	start := time.Now()
+	err := g.call(t, true)
+	elapsed := time.Since(start)
+
+	for _, o := range g.options.Observers {
+		o.TaskFinished(info, elapsed, err)
+	}
+
+	if g.options.TaskTiming {
+		g.stats.observe(elapsed)
+		if err != nil {
+			err = &TimedError{
+				Start:   start,
+				Err:     err,
+				Elapsed: elapsed,
+			}
+		}
+	}
+	return err
+}
+
+// call executes t, retrying it according to the Group's retry policy (see
+// WithRetry) if retry is true, and recovering any panic if the Group was
+// configured using the WithPanicRecovery() option. If t is named, any
+// resulting error is wrapped in a *TaskError.
+func (g *Group) call(t task, retry bool) (err error) {
+	if len(t.name) > 0 {
+		defer func() {
+			if err != nil {
+				err = &TaskError{
+					Err:  err,
+					Name: t.name,
+				}
+			}
+		}()
+	}
+
+	if handler := g.options.PanicHandler; handler != nil {
+		defer func() {
+			if v := recover(); v != nil {
+				err = handler(v, debug.Stack())
+			}
+		}()
+	} else if g.options.PanicRecovery {
+		defer func() {
+			if perr := errors.Recover(recover()); perr != nil {
+				err = perr
+			}
+		}()
+	}
+	if !retry {
+		return t.fn()
+	}
+	return g.retry(t.fn)
+}
+
+// retry calls fn, calling it again while it fails according to the Group's
+// retry policy (see WithRetry).
+func (g *Group) retry(fn ErrFunc) error {
+	err := fn()
+	for attempt := 1; attempt < g.options.RetryAttempts; attempt++ {
+		if !g.retryable(err) || !g.backoff(attempt) {
+			break
+		}
+		err = fn()
+	}
+	return err
+}
+
+func (g *Group) retryable(err error) bool {
+	if err == nil || g.ignored(err) || g.fatal(err) {
+		return false
+	}
+	return g.options.RetryIf == nil || g.options.RetryIf(err)
+}
+
+// backoff waits before the given retry attempt, reporting whether the retry
+// should proceed.
+func (g *Group) backoff(attempt int) bool {
+	var delay time.Duration
+	if g.options.RetryBackoff != nil {
+		delay = g.options.RetryBackoff(attempt)
+	}
+
+	var done <-chan struct{}
+	if g.ctx != nil {
+		if g.ctx.Err() != nil {
+			return false
+		}
+		done = g.ctx.Done()
+	}
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// taskName returns the name of fn if the Group was configured using the
+// WithTaskNames() option, or an empty string otherwise.
+func (g *Group) taskName(fn any) string {
+	if !g.options.TaskNames {
+		return ""
+	}
+	return funcName(fn)
+}
+
+func (g *Group) appendError(t task, err error) {
+	if err = g.filterError(err); err != nil {
+		g.appendFailure(t, err)
+	}
+}
+
+// filterError counts a function's completion, and returns err unless it is
+// nil, ignored, or discarded, transforming it if needed.
+func (g *Group) filterError(err error) error {
+	g.stats.completed.Add(1)
+	if err == nil {
+		g.stats.succeeded.Add(1)
+		return nil
+	}
+
+	if err = g.transformError(err); err == nil {
+		g.stats.ignored.Add(1)
+	}
+	return err
+}
+
+// transformError returns err, which must be non-nil, unless it is ignored or
+// discarded by the Group's error transform, transforming it if needed.
+func (g *Group) transformError(err error) error {
+	if g.ignored(err) {
+		return nil
+	}
+	if g.options.ErrorTransform != nil {
+		return g.options.ErrorTransform(err)
+	}
+	return err
+}
+
+// appendFailure counts and records err, which has been filtered.
+func (g *Group) appendFailure(t task, err error) {
+	g.stats.failed.Add(1)
+	g.reportFailure(t, err)
+}
+
+// reportFailure records err, which has been filtered, passing it to the
+// Group's OnError function if it was recorded.
+func (g *Group) reportFailure(t task, err error) {
+	if g.recordError(t, err) && g.options.OnError != nil {
+		g.options.OnError(err)
+	}
+}
+
+func (g *Group) ignored(err error) bool {
 	for _, ignored := range g.options.IgnoredErrors {
 		if errors.Is(err, ignored) {
-			return
+			return true
 		}
 	}
 
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	for _, ignore := range g.options.IgnoreFuncs {
+		if ignore(err) {
+			return true
+		}
+	}
 
-	if g.err != nil && g.options.FirstOnly {
-		return
+	return false
+}
+
+// recordError records err, reporting whether it was recorded rather than
+// discarded or omitted. To avoid contention between concurrently failing
+// functions, errors are pushed to a lock-free list that is only collected
+// into g.errs while holding g.mu (see collect).
+func (g *Group) recordError(t task, err error) bool {
+	if g.unsampled() || g.discard(g.numErrs.Add(1)) {
+		return false
+	}
+
+	node := &errNode{
+		err: err,
+		seq: t.seq,
+	}
+	for {
+		node.next = g.pending.Load()
+		if g.pending.CompareAndSwap(node.next, node) {
+			break
+		}
+	}
+
+	// If errors are being streamed (see Errors), collect the error now so
+	// that it is delivered immediately. Because streaming is enabled before
+	// the stream's initial errors are collected, every error is either
+	// collected by Errors or collected here.
+	if g.streaming.Load() {
+		g.mu.Lock()
+		g.collect()
+		g.mu.Unlock()
+	}
+
+	if g.cancel != nil && (g.options.CancelOnError || g.fatal(err)) {
+		g.cancel(err)
+	}
+	return true
+}
+
+// fatal reports whether err is fatal and the Group was configured using the
+// WithStopOnFatal() option.
+func (g *Group) fatal(err error) bool {
+	return g.options.StopOnFatal && errors.IsFatal(err)
+}
+
+// unsampled reports whether an error is left out by the Group's sampling (see
+// WithSampling), counting the error as unsampled if so. The first error is
+// always sampled.
+func (g *Group) unsampled() bool {
+	rate := g.options.SampleRate
+	if rate <= 0 || rate >= 1 || g.numErrs.Load() == 0 || rand.Float64() < rate {
+		return false
+	}
+
+	g.sampledOut.Add(1)
+	return true
+}
+
+// discard reports whether the nth error recorded by the Group exceeds its
+// limits (see WithFirstOnly, WithFirstN, and WithMaxErrors), counting the
+// error as omitted if needed.
+func (g *Group) discard(n int64) bool {
+	first := int64(g.options.FirstN)
+	if g.options.FirstOnly {
+		first = 1
+	}
+	if first > 0 && n > first {
+		return true
 	}
 
-	g.err = multierr.Append(g.err, err)
+	if limit := int64(g.options.MaxErrors); limit > 0 && n > limit {
+		g.omitted.Add(1)
+		return true
+	}
+	return false
+}
+
+// An errNode is an entry in a Group's list of errors pending collection.
+type errNode struct {
+	next *errNode
+	err  error
+	seq  uint64
+}
+
+// collect moves pending errors into g.errs in the order in which they were
+// recorded, delivering them to the Group's stream, if any. g.mu must be held.
+func (g *Group) collect() {
+	var head *errNode
+	for node := g.pending.Swap(nil); node != nil; {
+		next := node.next
+		node.next = head
+		head, node = node, next
+	}
+
+	for node := head; node != nil; node = node.next {
+		g.errs = append(g.errs, node.err)
+		if g.options.OrderedErrors {
+			g.seqs = append(g.seqs, node.seq)
+		}
+		if g.stream != nil {
+			g.stream.push(node.err)
+		}
+	}
 }
 
 // WithoutContext wraps a ContextErrFunc in an ErrFunc, providing a background
-// context to the given ContextErrFunc.
+// context to the given ContextErrFunc. To provide the Group's own context
+// instead, use Group.AddContext.
 func WithoutContext(fn ContextErrFunc) ErrFunc {
 	return func() error {
 		return fn(context.Background())
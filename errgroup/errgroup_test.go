@@ -2,17 +2,24 @@ package errgroup_test
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"io"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
 	"go.mway.dev/errors/errgroup"
-	"go.uber.org/multierr"
 )
 
 var (
+	_errSink error
+
 	errA = errors.New("a")
 	errB = errors.New("b")
 	errC = errors.New("c")
@@ -41,7 +48,7 @@ func TestErrGroup(t *testing.T) {
 
 func TestErrGroupInline(t *testing.T) {
 	var (
-		expectErr = multierr.Combine(errA, errB, errC)
+		expectErr = errors.Join(errA, errB, errC)
 		err       = errgroup.AllInline(
 			func() error {
 				time.Sleep(100 * time.Millisecond)
@@ -128,6 +135,398 @@ func TestErrGroupIgnoredErrors(t *testing.T) {
 	require.EqualError(t, g.Wait(), expectErr.Error())
 }
 
+func TestErrGroupJoin(t *testing.T) {
+	err := errgroup.AllInline(
+		func() error { return errA },
+		func() error { return errB },
+	)
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+
+	g := errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithJoin(func(errs ...error) error {
+			msgs := make([]string, 0, len(errs))
+			for _, err := range errs {
+				msgs = append(msgs, err.Error())
+			}
+			return errors.New(strings.Join(msgs, "; "))
+		}),
+	)
+	g.Add(
+		func() error { return errA },
+		func() error { return errB },
+	)
+	require.EqualError(t, g.Wait(), "a; b")
+}
+
+func TestErrGroupSingleError(t *testing.T) {
+	err := errgroup.All(
+		func() error { return nil },
+		func() error { return errA },
+	)
+	require.Equal(t, errA, err)
+}
+
+func TestErrGroupIgnoreFunc(t *testing.T) {
+	g := errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithIgnoreFunc(nil),
+		errgroup.WithIgnoreFunc(func(err error) bool {
+			_, ok := errors.AsType[*net.DNSError](err)
+			return ok
+		}),
+		errgroup.WithIgnoreFunc(func(err error) bool {
+			return errors.Is(err, errA)
+		}),
+	)
+
+	g.Add(
+		func() error { return errors.Wrap(&net.DNSError{Err: "no such host"}, "lookup") },
+		func() error { return errA },
+		func() error { return errC },
+	)
+
+	require.Equal(t, errC, g.Wait())
+}
+
+func TestErrGroupMaxErrors(t *testing.T) {
+	g := errgroup.New(errgroup.WithInline(), errgroup.WithMaxErrors(2))
+	for i := 0; i < 5; i++ {
+		g.Add(func() error { return errA })
+	}
+	g.Add(func() error { return nil })
+
+	err := g.Wait()
+	require.EqualError(t, err, "a\na\n3 more error(s) omitted")
+
+	truncated, ok := errors.AsType[*errgroup.TruncatedError](err)
+	require.True(t, ok)
+	require.Equal(t, 3, truncated.Omitted)
+}
+
+func TestErrGroupFirstN(t *testing.T) {
+	cases := map[string]struct {
+		wantErr  string
+		giveOpts []errgroup.Option
+	}{
+		"first n": {
+			giveOpts: []errgroup.Option{errgroup.WithFirstN(2)},
+			wantErr:  "a\nb",
+		},
+		"first one": {
+			giveOpts: []errgroup.Option{errgroup.WithFirstN(1)},
+			wantErr:  "a",
+		},
+		"max errors": {
+			giveOpts: []errgroup.Option{
+				errgroup.WithFirstN(3),
+				errgroup.WithMaxErrors(2),
+			},
+			wantErr: "a\nb\n1 more error(s) omitted",
+		},
+		"unlimited": {
+			giveOpts: []errgroup.Option{errgroup.WithFirstN(0)},
+			wantErr:  "a\nb\nc\na\nb",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := errgroup.New(append(tt.giveOpts, errgroup.WithInline())...)
+			g.Add(
+				func() error { return errA },
+				func() error { return errB },
+				func() error { return errC },
+				func() error { return errA },
+				func() error { return errB },
+			)
+			require.EqualError(t, g.Wait(), tt.wantErr)
+			require.Equal(t, 5, g.Stats().Failed)
+		})
+	}
+}
+
+func TestWithSampling(t *testing.T) {
+	cases := map[string]struct {
+		giveRate float64
+		wantMin  int
+		wantMax  int
+	}{
+		"sampled": {
+			giveRate: 0.1,
+			wantMin:  1,
+			wantMax:  500,
+		},
+		"disabled": {
+			giveRate: 0,
+			wantMin:  1000,
+			wantMax:  1000,
+		},
+		"all": {
+			giveRate: 1,
+			wantMin:  1000,
+			wantMax:  1000,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			var (
+				g = errgroup.New(
+					errgroup.WithInline(),
+					errgroup.WithSampling(tt.giveRate),
+				)
+				calls int
+			)
+			for i := 0; i < 1000; i++ {
+				g.Add(func() error {
+					calls++
+					return fmt.Errorf("error %d", calls)
+				})
+			}
+
+			err := g.Wait()
+			require.ErrorContains(t, err, "error 1\n")
+			require.Equal(t, 1000, g.Stats().Failed)
+
+			recorded := len(err.(interface{ Unwrap() []error }).Unwrap())
+			if sampled, ok := errors.AsType[*errgroup.SampledError](err); ok {
+				recorded--
+				require.Equal(t, 1000-recorded, sampled.Unsampled)
+			}
+			_, truncated := errors.AsType[*errgroup.TruncatedError](err)
+			require.False(t, truncated)
+			require.GreaterOrEqual(t, recorded, tt.wantMin)
+			require.LessOrEqual(t, recorded, tt.wantMax)
+		})
+	}
+}
+
+func TestWithSampling_MaxErrors(t *testing.T) {
+	g := errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithSampling(0.5),
+		errgroup.WithMaxErrors(1),
+	)
+	for i := 0; i < 100; i++ {
+		g.Add(func() error { return errA })
+	}
+
+	err := g.Wait()
+	truncated, ok := errors.AsType[*errgroup.TruncatedError](err)
+	require.True(t, ok)
+	sampled, ok := errors.AsType[*errgroup.SampledError](err)
+	require.True(t, ok)
+	require.Equal(t, 99, truncated.Omitted+sampled.Unsampled)
+	require.Contains(t, err.Error(), "error(s) not sampled")
+}
+
+func TestErrGroupOrderedErrors(t *testing.T) {
+	g := errgroup.New(errgroup.WithOrderedErrors())
+	g.Add(
+		func() error {
+			time.Sleep(20 * time.Millisecond)
+			return errA
+		},
+		func() error { return nil },
+		func() error {
+			time.Sleep(10 * time.Millisecond)
+			return errB
+		},
+		func() error { return errC },
+	)
+
+	require.EqualError(t, g.Wait(), "a\nb\nc")
+}
+
+func TestGroupStats(t *testing.T) {
+	var (
+		g = errgroup.New(
+			errgroup.WithIgnoredErrors(io.EOF),
+			errgroup.WithErrorTransform(func(err error) error {
+				if errors.Is(err, errC) {
+					return nil
+				}
+				return err
+			}),
+			errgroup.WithFirstOnly(),
+		)
+		done = make(chan struct{})
+	)
+	require.Equal(t, errgroup.Stats{}, g.Stats())
+
+	g.Add(
+		func() error { return nil },
+		func() error { return nil },
+		func() error { return io.EOF },
+		func() error { return errA },
+		func() error { return errB },
+		func() error { return errC },
+		func() error {
+			<-done
+			return nil
+		},
+	)
+	require.Equal(t, 7, g.Stats().Scheduled)
+
+	close(done)
+	require.Error(t, g.Wait())
+	require.Equal(t, errgroup.Stats{
+		Scheduled: 7,
+		Completed: 7,
+		Succeeded: 3,
+		Failed:    2,
+		Ignored:   2,
+	}, g.Stats())
+}
+
+func TestGroupReset(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background(), errgroup.WithInline())
+
+	g.Add(func() error { return errA })
+	require.Equal(t, errA, g.Wait())
+	require.Error(t, ctx.Err())
+
+	g.Reset()
+	require.Equal(t, errgroup.Stats{}, g.Stats())
+
+	var have context.Context
+	g.AddContext(func(ctx context.Context) error {
+		have = ctx
+		return ctx.Err()
+	})
+	require.NoError(t, g.Wait())
+	require.NotEqual(t, ctx, have)
+	require.Equal(t, errgroup.Stats{
+		Scheduled: 1,
+		Completed: 1,
+		Succeeded: 1,
+	}, g.Stats())
+
+	g.Reset()
+	g.Add(func() error { return errB })
+	require.Equal(t, errB, g.Wait())
+}
+
+func TestGroupReset_RetainingJoin(t *testing.T) {
+	var retained [][]error
+	g := errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithJoin(func(errs ...error) error {
+			retained = append(retained, errs)
+			return errors.Join(errs...)
+		}),
+	)
+
+	g.Add(
+		func() error { return errA },
+		func() error { return errB },
+	)
+	require.Error(t, g.Wait())
+
+	g.Reset()
+	g.Add(
+		func() error { return errB },
+		func() error { return errA },
+	)
+	require.Error(t, g.Wait())
+
+	require.Equal(t, [][]error{{errA, errB}, {errB, errA}}, retained)
+}
+
+func TestGroupReset_Active(t *testing.T) {
+	var (
+		g    errgroup.Group
+		done = make(chan struct{})
+	)
+	g.Add(func() error {
+		<-done
+		return nil
+	})
+
+	require.Panics(t, g.Reset)
+	close(done)
+	require.NoError(t, g.Wait())
+	require.NotPanics(t, g.Reset)
+}
+
+func TestGroupErrors(t *testing.T) {
+	var (
+		g    errgroup.Group
+		done = make(chan struct{})
+	)
+	g.Add(func() error { return errA })
+
+	errs := g.Errors()
+	require.Equal(t, errA, <-errs)
+
+	g.Add(
+		func() error { return nil },
+		func() error {
+			<-done
+			return errB
+		},
+	)
+	close(done)
+	require.Equal(t, errB, <-errs)
+
+	require.Error(t, g.Wait())
+	_, ok := <-errs
+	require.False(t, ok)
+}
+
+func TestGroupErrors_Concurrent(t *testing.T) {
+	var (
+		g    errgroup.Group
+		have = make(chan int)
+	)
+
+	for i := 0; i < 100; i++ {
+		g.Add(failingTask)
+		if i == 50 {
+			go func() {
+				n := 0
+				for range g.Errors() {
+					n++
+				}
+				have <- n
+			}()
+		}
+	}
+
+	require.Error(t, g.Wait())
+	require.Equal(t, 100, <-have)
+}
+
+func TestGroupErrors_Undrained(t *testing.T) {
+	g := errgroup.New(errgroup.WithInline())
+	errs := g.Errors()
+	g.Add(failingTask, failingTask, failingTask)
+	require.Error(t, g.Wait())
+
+	var have []error
+	for err := range errs {
+		have = append(have, err)
+	}
+	require.Equal(t, []error{errA, errA, errA}, have)
+}
+
+func TestGroupErrors_AfterWait(t *testing.T) {
+	g := errgroup.New(errgroup.WithInline())
+	g.Add(failingTask)
+	require.Equal(t, errA, g.Wait())
+
+	errs := g.Errors()
+	require.Equal(t, errA, <-errs)
+	_, ok := <-errs
+	require.False(t, ok)
+
+	g.Reset()
+	require.NotEqual(t, errs, g.Errors())
+}
+
 func TestErrGroupNoErrors(t *testing.T) {
 	err := errgroup.All(
 		func() error { return nil },
@@ -138,6 +537,1112 @@ func TestErrGroupNoErrors(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWithContext(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background())
+
+	var (
+		started = make(chan struct{})
+		results = make(chan error, 1)
+	)
+	g.AddContext(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		results <- context.Cause(ctx)
+		return ctx.Err()
+	})
+
+	<-started
+	require.NoError(t, ctx.Err())
+
+	g.AddContext(func(context.Context) error {
+		return errA
+	})
+
+	require.Equal(t, errA, <-results)
+	require.ErrorIs(t, g.Wait(), errA)
+	require.ErrorIs(t, g.Wait(), context.Canceled)
+	require.Equal(t, errA, context.Cause(ctx))
+}
+
+func TestWithContext_Wait(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background(), errgroup.WithInline())
+
+	var have context.Context
+	g.AddContext(func(ctx context.Context) error {
+		have = ctx
+		return nil
+	})
+	require.Equal(t, ctx, have)
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, g.Wait())
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.ErrorIs(t, context.Cause(ctx), context.Canceled)
+}
+
+func TestWithContext_IgnoredErrors(t *testing.T) {
+	g, ctx := errgroup.WithContext(
+		context.Background(),
+		errgroup.WithInline(),
+		errgroup.WithIgnoredErrors(io.EOF),
+	)
+
+	g.Add(func() error { return io.EOF })
+	require.NoError(t, ctx.Err())
+	require.NoError(t, g.Wait())
+}
+
+func TestWithCancelOnError(t *testing.T) {
+	g := errgroup.New(errgroup.WithCancelOnError())
+
+	started := make(chan struct{})
+	g.AddContext(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+
+	<-started
+	g.AddContext(func(context.Context) error {
+		return errA
+	})
+
+	err := g.Wait()
+	require.ErrorIs(t, err, errA)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestGroupLimit(t *testing.T) {
+	const limit = 3
+
+	cases := map[string]func() *errgroup.Group{
+		"option": func() *errgroup.Group {
+			return errgroup.New(errgroup.WithLimit(limit))
+		},
+		"set limit": func() *errgroup.Group {
+			var g errgroup.Group
+			g.SetLimit(limit)
+			return &g
+		},
+	}
+
+	for name, newGroup := range cases {
+		t.Run(name, func(t *testing.T) {
+			var (
+				g      = newGroup()
+				active atomic.Int32
+				peak   atomic.Int32
+			)
+
+			for i := 0; i < 4*limit; i++ {
+				g.Add(func() error {
+					n := active.Add(1)
+					defer active.Add(-1)
+
+					for {
+						prev := peak.Load()
+						if n <= prev || peak.CompareAndSwap(prev, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					return nil
+				})
+			}
+
+			require.NoError(t, g.Wait())
+			require.LessOrEqual(t, peak.Load(), int32(limit))
+			require.Positive(t, peak.Load())
+		})
+	}
+}
+
+func TestGroupSetLimit_Active(t *testing.T) {
+	var (
+		g    = errgroup.New(errgroup.WithLimit(1))
+		done = make(chan struct{})
+	)
+	g.Add(func() error {
+		<-done
+		return nil
+	})
+
+	require.Panics(t, func() { g.SetLimit(2) })
+	close(done)
+	require.NoError(t, g.Wait())
+
+	require.NotPanics(t, func() { g.SetLimit(0) })
+}
+
+func TestWithWorkers(t *testing.T) {
+	var (
+		g            = errgroup.New(errgroup.WithWorkers(3))
+		active, peak atomic.Int64
+		goroutines   = runtime.NumGoroutine()
+	)
+
+	for i := 0; i < 100; i++ {
+		i := i
+		g.Add(func() error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				prev := peak.Load()
+				if n <= prev || peak.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+
+			if i%25 == 0 {
+				return errA
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	require.EqualError(t, err, "a\na\na\na")
+	require.LessOrEqual(t, peak.Load(), int64(3))
+	require.Equal(t, 100, g.Stats().Completed)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if runtime.NumGoroutine() <= goroutines {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+
+	g.Reset()
+	g.Add(failingTask)
+	require.Equal(t, errA, g.Wait())
+}
+
+func TestWithWorkers_TryAdd(t *testing.T) {
+	var (
+		g       = errgroup.New(errgroup.WithWorkers(1))
+		started = make(chan struct{})
+		done    = make(chan struct{})
+	)
+
+	require.True(t, g.TryAdd(func() error {
+		close(started)
+		<-done
+		return nil
+	}))
+	<-started
+	require.True(t, g.TryAdd(failingTask))
+	require.False(t, g.TryAdd(failingTask))
+	require.Equal(t, 2, g.Stats().Scheduled)
+
+	close(done)
+	require.Equal(t, errA, g.Wait())
+}
+
+func TestWithWorkers_TryAddSeq(t *testing.T) {
+	var (
+		observer recordingObserver
+		g        = errgroup.New(
+			errgroup.WithWorkers(1),
+			errgroup.WithObserver(&observer),
+		)
+		started = make(chan struct{})
+		done    = make(chan struct{})
+	)
+
+	require.True(t, g.TryAdd(func() error {
+		close(started)
+		<-done
+		return nil
+	}))
+	<-started
+	require.True(t, g.TryAdd(failingTask))
+	require.False(t, g.TryAdd(failingTask))
+
+	close(done)
+	g.Add(failingTask)
+	require.ErrorIs(t, g.Wait(), errA)
+
+	seqs := make(map[string]bool)
+	for _, event := range observer.events {
+		if fields := strings.Fields(event); fields[0] == "start" {
+			seqs[fields[len(fields)-1]] = true
+		}
+	}
+	require.Equal(t, map[string]bool{"1": true, "2": true, "3": true}, seqs)
+}
+
+func TestWithWorkers_WaitTimeout(t *testing.T) {
+	var (
+		g    = errgroup.New(errgroup.WithWorkers(1))
+		done = make(chan struct{})
+	)
+	g.Add(func() error {
+		<-done
+		return errA
+	})
+
+	err, ok := g.WaitTimeout(time.Millisecond)
+	require.False(t, ok)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(done)
+	require.Equal(t, errA, g.Wait())
+}
+
+func TestGroupAddFrom(t *testing.T) {
+	var (
+		g     = errgroup.New(errgroup.WithLimit(2))
+		fns   = make(chan errgroup.ErrFunc)
+		calls atomic.Int64
+	)
+
+	go func() {
+		defer close(fns)
+		for i := 0; i < 10; i++ {
+			i := i
+			fns <- func() error {
+				calls.Add(1)
+				if i == 5 {
+					return errA
+				}
+				return nil
+			}
+		}
+	}()
+
+	require.NoError(t, g.AddFrom(context.Background(), fns))
+	require.Equal(t, errA, g.Wait())
+	require.Equal(t, int64(10), calls.Load())
+}
+
+func TestGroupAddFrom_Canceled(t *testing.T) {
+	var (
+		g           errgroup.Group
+		fns         = make(chan errgroup.ErrFunc, 1)
+		ctx, cancel = context.WithCancelCause(context.Background())
+	)
+
+	fns <- failingTask
+	go func() {
+		for g.Stats().Scheduled == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel(errB)
+	}()
+
+	require.Equal(t, errB, g.AddFrom(ctx, fns))
+	require.Equal(t, errA, g.Wait())
+}
+
+func TestGroupDetach(t *testing.T) {
+	var (
+		reported = make(chan error, 3)
+		done     = make(chan struct{})
+		g        = errgroup.New(
+			errgroup.WithCancelOnError(),
+			errgroup.WithPanicRecovery(),
+			errgroup.WithIgnoredErrors(io.EOF),
+			errgroup.WithOnError(func(err error) {
+				reported <- err
+			}),
+		)
+	)
+
+	g.Detach(func() error {
+		<-done
+		return errA
+	})
+	g.Detach(func() error { return io.EOF })
+	g.Detach(func() error { panic("oops") })
+	g.Add(func() error { return nil })
+
+	require.NoError(t, g.Wait())
+	require.Equal(t, errgroup.Stats{
+		Scheduled: 1,
+		Completed: 1,
+		Succeeded: 1,
+	}, g.Stats())
+
+	panicErr, ok := errors.AsType[*errors.PanicError](<-reported)
+	require.True(t, ok)
+	require.Equal(t, "oops", panicErr.Value)
+
+	close(done)
+	require.Equal(t, errA, <-reported)
+	require.Empty(t, reported)
+}
+
+func TestGroupDetach_NoRetry(t *testing.T) {
+	var (
+		reported = make(chan error, 1)
+		calls    atomic.Int32
+		g        = errgroup.New(
+			errgroup.WithRetry(3, nil, nil),
+			errgroup.WithOnError(func(err error) {
+				reported <- err
+			}),
+		)
+	)
+
+	g.Detach(func() error {
+		calls.Add(1)
+		return errA
+	})
+	require.Equal(t, errA, <-reported)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestGroupAddWeighted(t *testing.T) {
+	var (
+		g            = errgroup.New(errgroup.WithMaxWeight(10))
+		active, peak atomic.Int64
+	)
+
+	task := func(weight int64) errgroup.ErrFunc {
+		return func() error {
+			n := active.Add(weight)
+			defer active.Add(-weight)
+			for {
+				prev := peak.Load()
+				if n <= prev || peak.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			if weight == 10 {
+				return errA
+			}
+			return nil
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		g.AddWeighted(int64(i%4+1), task(int64(i%4+1)))
+	}
+	g.AddWeighted(10, task(10))
+	g.AddWeighted(0, task(0))
+
+	require.Equal(t, errA, g.Wait())
+	require.Equal(t, int64(10), peak.Load())
+	require.Panics(t, func() { g.AddWeighted(11, failingTask) })
+	require.Panics(t, func() { g.AddWeighted(-1, failingTask) })
+}
+
+func TestGroupAddWeighted_Unbounded(t *testing.T) {
+	g := errgroup.New(errgroup.WithInline())
+	g.AddWeighted(1<<40, failingTask)
+	require.Equal(t, errA, g.Wait())
+}
+
+func TestGroupTryAdd(t *testing.T) {
+	var (
+		g    = errgroup.New(errgroup.WithLimit(1))
+		done = make(chan struct{})
+	)
+
+	require.True(t, g.TryAdd(func() error {
+		<-done
+		return errA
+	}))
+	require.False(t, g.TryAdd(func() error {
+		return errB
+	}))
+
+	close(done)
+	require.Equal(t, errA, g.Wait())
+}
+
+func TestGroupTryAdd_Unlimited(t *testing.T) {
+	var g errgroup.Group
+	for i := 0; i < 10; i++ {
+		require.True(t, g.TryAdd(func() error { return nil }))
+	}
+	require.NoError(t, g.Wait())
+
+	g2 := errgroup.New(errgroup.WithInline(), errgroup.WithLimit(1))
+	require.True(t, g2.TryAdd(func() error { return errA }))
+	require.True(t, g2.TryAdd(func() error { return errB }))
+	require.ErrorIs(t, g2.Wait(), errB)
+}
+
+func TestWithPanicRecovery(t *testing.T) {
+	for _, inline := range []bool{false, true} {
+		t.Run(fmt.Sprintf("inline=%t", inline), func(t *testing.T) {
+			opts := []errgroup.Option{errgroup.WithPanicRecovery()}
+			if inline {
+				opts = append(opts, errgroup.WithInline())
+			}
+
+			g := errgroup.New(opts...)
+			g.Add(
+				func() error { panic("boom") },
+				func() error { panic(errA) },
+				func() error { return errB },
+			)
+
+			err := g.Wait()
+			require.ErrorIs(t, err, errA)
+			require.ErrorIs(t, err, errB)
+
+			panicErr, ok := errors.AsType[*errors.PanicError](err)
+			require.True(t, ok)
+			require.Contains(t, string(panicErr.Stack), "errgroup_test.go")
+			require.Contains(t, err.Error(), "panic: boom")
+		})
+	}
+}
+
+func TestWithPanicHandler(t *testing.T) {
+	var (
+		stacks []string
+		g      = errgroup.New(
+			errgroup.WithInline(),
+			errgroup.WithPanicHandler(nil),
+			errgroup.WithPanicHandler(func(v any, stack []byte) error {
+				stacks = append(stacks, string(stack))
+				if v == "ignore" {
+					return nil
+				}
+				return fmt.Errorf("recovered: %v", v)
+			}),
+		)
+	)
+
+	g.Add(
+		func() error { panic("ignore") },
+		func() error { panic("boom") },
+		func() error { return errA },
+	)
+	require.EqualError(t, g.Wait(), "recovered: boom\na")
+	require.Len(t, stacks, 2)
+	require.Contains(t, stacks[0], "TestWithPanicHandler")
+	require.Equal(t, 1, g.Stats().Succeeded)
+
+	g = errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithPanicHandler(func(v any, _ []byte) error {
+			panic(fmt.Sprintf("rethrown: %v", v))
+		}),
+	)
+	require.PanicsWithValue(t, "rethrown: boom", func() {
+		g.Add(func() error { panic("boom") })
+	})
+}
+
+func TestWithTaskTimeout(t *testing.T) {
+	g, ctx := errgroup.WithContext(
+		context.Background(),
+		errgroup.WithTaskTimeout(10*time.Millisecond),
+	)
+
+	g.AddContext(
+		slowTask,
+		func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				return errA
+			}
+			return nil
+		},
+	)
+
+	err := g.Wait()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, err, context.Cause(ctx))
+
+	timeoutErr, ok := errors.AsType[*errgroup.TaskTimeoutError](err)
+	require.True(t, ok)
+	require.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	require.True(t, strings.HasSuffix(timeoutErr.Task, ".slowTask"), timeoutErr.Task)
+	require.Equal(
+		t,
+		"task "+timeoutErr.Task+" timed out after 10ms: context deadline exceeded",
+		err.Error(),
+	)
+}
+
+func TestWithTaskTimeout_ParentCanceled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g, _ := errgroup.WithContext(parent, errgroup.WithTaskTimeout(time.Hour))
+	g.AddContext(slowTask)
+
+	err := g.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, ok := errors.AsType[*errgroup.TaskTimeoutError](err)
+	require.False(t, ok)
+}
+
+func slowTask(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestGroupWaitContext(t *testing.T) {
+	var (
+		g    = errgroup.New(errgroup.WithCancelOnError())
+		done = make(chan struct{})
+	)
+	defer close(done)
+
+	g.Add(
+		func() error { return errA },
+		func() error {
+			<-done
+			return errB
+		},
+	)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errC)
+
+	err := g.WaitContext(ctx)
+	require.ErrorIs(t, err, errC)
+	require.NotErrorIs(t, err, errB)
+
+	// errA may not have been recorded yet, so wait for it with a generous
+	// timeout.
+	err, ok := g.WaitTimeout(50 * time.Millisecond)
+	require.False(t, ok)
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotErrorIs(t, err, errB)
+}
+
+func TestGroupWaitTimeout(t *testing.T) {
+	g := errgroup.New()
+	g.Add(func() error { return errA })
+
+	err, ok := g.WaitTimeout(time.Minute)
+	require.True(t, ok)
+	require.Equal(t, errA, err)
+
+	require.NoError(t, errgroup.New().WaitContext(context.Background()))
+}
+
+func TestGroupDone(t *testing.T) {
+	var (
+		g    = errgroup.New(errgroup.WithLimit(2))
+		done = make(chan struct{})
+	)
+	g.Add(
+		func() error {
+			<-done
+			return errA
+		},
+		func() error { return nil },
+	)
+
+	select {
+	case <-g.Done():
+		require.FailNow(t, "group done before its functions returned")
+	case <-time.After(10 * time.Millisecond):
+	}
+	require.Equal(t, g.Done(), g.Done())
+
+	close(done)
+	<-g.Done()
+	require.Equal(t, errA, g.Wait())
+
+	g.Reset()
+	<-g.Done()
+	require.NoError(t, g.Wait())
+}
+
+func TestGroupShutdown(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.AddContext(func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	g.Add(failingTask)
+	for g.Stats().Completed == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	err := g.Shutdown(context.Background())
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, context.Cause(ctx), errA)
+
+	g, ctx = errgroup.WithContext(context.Background())
+	g.AddContext(func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	require.Equal(t, errgroup.ErrShutdown, g.Shutdown(context.Background()))
+	require.Equal(t, errgroup.ErrShutdown, context.Cause(ctx))
+}
+
+func TestGroupShutdown_Stragglers(t *testing.T) {
+	var (
+		g, _ = errgroup.WithContext(context.Background())
+		done = make(chan struct{})
+	)
+	defer close(done)
+
+	g.Add(failingTask)
+	for g.Stats().Completed == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 2; i++ {
+		g.Add(func() error {
+			<-done
+			return nil
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err := g.Shutdown(ctx)
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	shutdownErr, ok := errors.AsType[*errgroup.ShutdownError](err)
+	require.True(t, ok)
+	require.Equal(t, 2, shutdownErr.Remaining)
+	require.EqualError(t, shutdownErr, "2 task(s) did not exit: context deadline exceeded")
+}
+
+func TestGroupSubgroup(t *testing.T) {
+	var (
+		g, ctx = errgroup.WithContext(context.Background())
+		sub    = g.Subgroup(errgroup.WithOrderedErrors())
+		done   = make(chan struct{})
+		waited atomic.Bool
+	)
+
+	sub.Add(
+		func() error {
+			<-done
+			return errA
+		},
+		func() error { return errB },
+	)
+	sub.AddContext(func(subCtx context.Context) error {
+		<-ctx.Done()
+		return subCtx.Err()
+	})
+	go func() {
+		close(done)
+		time.Sleep(10 * time.Millisecond)
+		waited.Store(true)
+		_ = sub.Wait() //nolint:errcheck
+	}()
+
+	g.Add(failingTask)
+	err := g.Wait()
+	require.True(t, waited.Load())
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, errgroup.Stats{
+		Scheduled: 1,
+		Completed: 1,
+		Failed:    1,
+	}, g.Stats())
+	require.Equal(t, errgroup.Stats{
+		Scheduled: 3,
+		Completed: 3,
+		Failed:    3,
+	}, sub.Stats())
+}
+
+func TestGroupSubgroup_NoErrors(t *testing.T) {
+	var g errgroup.Group
+
+	sub := g.Subgroup(errgroup.WithInline())
+	sub.Add(func() error { return nil })
+	require.NoError(t, sub.Wait())
+	require.NoError(t, sub.Wait())
+	require.NoError(t, g.Wait())
+	require.Equal(t, errgroup.Stats{}, g.Stats())
+}
+
+func TestGroupSubgroup_Reset(t *testing.T) {
+	var g errgroup.Group
+
+	sub := g.Subgroup(errgroup.WithInline())
+	require.Panics(t, g.Reset)
+	require.NoError(t, sub.Wait())
+	require.NotPanics(t, g.Reset)
+}
+
+func TestGroupGo(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(1)
+
+	done := make(chan struct{})
+	g.Go(func() error {
+		<-done
+		return errA
+	})
+	require.False(t, g.TryGo(func() error { return errB }))
+
+	close(done)
+	require.Equal(t, errA, g.Wait())
+	require.Equal(t, errA, context.Cause(ctx))
+}
+
+func TestGroupAddNamed(t *testing.T) {
+	g := errgroup.New(errgroup.WithInline(), errgroup.WithPanicRecovery())
+	g.AddNamed("fetch a", func() error { return errA })
+	g.AddNamed("fetch b", func() error { return nil })
+	g.AddNamed("fetch c", func() error { panic("boom") })
+
+	err := g.Wait()
+	require.EqualError(t, err, "task fetch a: a\ntask fetch c: panic: boom")
+	require.ErrorIs(t, err, errA)
+
+	taskErr, ok := errors.AsType[*errgroup.TaskError](err)
+	require.True(t, ok)
+	require.Equal(t, "fetch a", taskErr.Name)
+	require.Equal(t, errA, taskErr.Err)
+}
+
+func TestWithTaskNames(t *testing.T) {
+	g := errgroup.New(errgroup.WithTaskNames())
+	g.Add(failingTask)
+	g.AddContext(func(context.Context) error { return errB })
+
+	err := g.Wait()
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.Contains(t, err.Error(), "errgroup_test.failingTask: a")
+	require.Contains(t, err.Error(), "errgroup_test.TestWithTaskNames.func1: b")
+}
+
+func failingTask() error {
+	return errA
+}
+
+func TestWithTaskTiming(t *testing.T) {
+	var (
+		g = errgroup.New(
+			errgroup.WithInline(),
+			errgroup.WithTaskTiming(),
+			errgroup.WithIgnoredErrors(io.EOF),
+		)
+		before = time.Now()
+	)
+
+	g.Add(
+		func() error {
+			time.Sleep(20 * time.Millisecond)
+			return errA
+		},
+		func() error {
+			time.Sleep(10 * time.Millisecond)
+			return io.EOF
+		},
+		func() error { return nil },
+	)
+
+	err := g.Wait()
+	require.ErrorIs(t, err, errA)
+	require.Regexp(t, `^task failed after \d+(\.\d+)?ms: a$`, err.Error())
+
+	timed, ok := errors.AsType[*errgroup.TimedError](err)
+	require.True(t, ok)
+	require.False(t, timed.Start.Before(before))
+	require.GreaterOrEqual(t, timed.Elapsed, 20*time.Millisecond)
+
+	stats := g.Stats()
+	require.Equal(t, 1, stats.Ignored)
+	require.Equal(t, timed.Elapsed, stats.MaxDuration)
+	require.GreaterOrEqual(t, stats.TotalDuration, 30*time.Millisecond)
+}
+
+func TestWithWrapMessage(t *testing.T) {
+	g := errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithWrapMessage("flush pipeline"),
+	)
+	g.Add(func() error { return nil })
+	require.NoError(t, g.Wait())
+
+	g.Reset()
+	g.Add(failingTask, func() error { return errB })
+	err := g.Wait()
+	require.EqualError(t, err, "flush pipeline: a\nb")
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+
+	g = errgroup.New(errgroup.WithWrapMessage("flush pipeline"))
+	g.Add(func() error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	err, ok := g.WaitTimeout(time.Millisecond)
+	require.False(t, ok)
+	require.EqualError(t, err, "flush pipeline: context deadline exceeded")
+}
+
+func TestWithErrorTransform(t *testing.T) {
+	g := errgroup.New(
+		errgroup.WithInline(),
+		errgroup.WithIgnoredErrors(io.EOF),
+		errgroup.WithErrorTransform(func(err error) error {
+			if errors.Is(err, errB) {
+				return nil
+			}
+			return errors.Wrap(err, "inner")
+		}),
+		errgroup.WithErrorTransform(nil),
+		errgroup.WithErrorTransform(func(err error) error {
+			return errors.Wrap(err, "outer")
+		}),
+	)
+	g.Add(
+		func() error { return errA },
+		func() error { return errB },
+		func() error { return io.EOF },
+		func() error { return nil },
+	)
+
+	err := g.Wait()
+	require.EqualError(t, err, "outer: inner: a")
+	require.ErrorIs(t, err, errA)
+}
+
+func TestWithOnError(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		first  []error
+		second []error
+		g      = errgroup.New(
+			errgroup.WithInline(),
+			errgroup.WithFirstOnly(),
+			errgroup.WithIgnoredErrors(io.EOF),
+			errgroup.WithOnError(func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				first = append(first, err)
+			}),
+			errgroup.WithOnError(nil),
+			errgroup.WithOnError(func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				second = append(second, err)
+			}),
+		)
+	)
+
+	g.Add(
+		func() error { return io.EOF },
+		func() error { return errA },
+		func() error { return errB },
+	)
+	require.Equal(t, errA, g.Wait())
+
+	require.Equal(t, []error{errA}, first)
+	require.Equal(t, []error{errA}, second)
+}
+
+func TestWithRetry(t *testing.T) {
+	cases := map[string]struct {
+		giveRetryIf  func(error) bool
+		wantErr      error
+		giveErrs     []error
+		giveAttempts int
+		wantCalls    int
+	}{
+		"recovers": {
+			giveAttempts: 3,
+			giveErrs:     []error{errA, errB, nil},
+			wantErr:      nil,
+			wantCalls:    3,
+		},
+		"exhausted": {
+			giveAttempts: 2,
+			giveErrs:     []error{errA, errB, nil},
+			wantErr:      errB,
+			wantCalls:    2,
+		},
+		"disabled": {
+			giveAttempts: 1,
+			giveErrs:     []error{errA, nil},
+			wantErr:      errA,
+			wantCalls:    1,
+		},
+		"retry if": {
+			giveAttempts: 3,
+			giveRetryIf: func(err error) bool {
+				return errors.Is(err, errA)
+			},
+			giveErrs:  []error{errA, errB, nil},
+			wantErr:   errB,
+			wantCalls: 2,
+		},
+		"ignored": {
+			giveAttempts: 3,
+			giveErrs:     []error{io.EOF, nil},
+			wantErr:      nil,
+			wantCalls:    1,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			var (
+				attempts []int
+				calls    int
+				g        = errgroup.New(
+					errgroup.WithIgnoredErrors(io.EOF),
+					errgroup.WithRetry(
+						tt.giveAttempts,
+						func(attempt int) time.Duration {
+							attempts = append(attempts, attempt)
+							return time.Millisecond
+						},
+						tt.giveRetryIf,
+					),
+				)
+			)
+
+			g.Add(func() error {
+				calls++
+				return tt.giveErrs[calls-1]
+			})
+			require.Equal(t, tt.wantErr, g.Wait())
+			require.Equal(t, tt.wantCalls, calls)
+			require.Len(t, attempts, tt.wantCalls-1)
+			for i, attempt := range attempts {
+				require.Equal(t, i+1, attempt)
+			}
+		})
+	}
+}
+
+func TestWithRetry_Canceled(t *testing.T) {
+	var (
+		calls       int
+		ctx, cancel = context.WithCancel(context.Background())
+		g, _        = errgroup.WithContext(
+			ctx,
+			errgroup.WithRetry(3, func(int) time.Duration { return time.Hour }, nil),
+		)
+	)
+
+	g.Add(func() error {
+		calls++
+		cancel()
+		return errA
+	})
+	require.Equal(t, errA, g.Wait())
+	require.Equal(t, 1, calls)
+}
+
+func TestWithStopOnFatal(t *testing.T) {
+	var (
+		opts = []errgroup.Option{
+			errgroup.WithStopOnFatal(),
+			errgroup.WithRetry(3, nil, nil),
+		}
+		calls atomic.Int64
+	)
+
+	// Ordinary errors are retried and do not cancel the Group's context.
+	g := errgroup.New(append(opts, errgroup.WithInline())...)
+	g.Add(func() error {
+		calls.Add(1)
+		return errA
+	})
+	g.AddContext(func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	require.Equal(t, errA, g.Wait())
+	require.Equal(t, int64(3), calls.Load())
+
+	// Fatal errors are not retried and cancel the Group's context.
+	var (
+		fatal = errors.Fatal(errB)
+		done  = make(chan error, 1)
+	)
+	calls.Store(0)
+	g = errgroup.New(opts...)
+	g.AddContext(func(ctx context.Context) error {
+		<-ctx.Done()
+		done <- context.Cause(ctx)
+		return nil
+	})
+	g.Add(func() error {
+		calls.Add(1)
+		return fatal
+	})
+
+	require.Equal(t, fatal, <-done)
+	require.Equal(t, fatal, g.Wait())
+	require.Equal(t, int64(1), calls.Load())
+}
+
+type recordingObserver struct {
+	events []string
+	mu     sync.Mutex
+}
+
+func (o *recordingObserver) TaskStarted(info errgroup.TaskInfo) {
+	o.record(fmt.Sprintf("start %s %d", info.Name, info.Seq))
+}
+
+func (o *recordingObserver) TaskFinished(
+	info errgroup.TaskInfo,
+	_ time.Duration,
+	err error,
+) {
+	o.record(fmt.Sprintf("finish %s %d %v", info.Name, info.Seq, err))
+}
+
+func (o *recordingObserver) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func TestWithObserver(t *testing.T) {
+	var (
+		first, second recordingObserver
+		g             = errgroup.New(
+			errgroup.WithInline(),
+			errgroup.WithIgnoredErrors(io.EOF),
+			errgroup.WithObserver(&first),
+			errgroup.WithObserver(&second),
+		)
+	)
+
+	g.AddNamed("x", failingTask)
+	g.Add(func() error {
+		time.Sleep(time.Millisecond)
+		return io.EOF
+	})
+	require.EqualError(t, g.Wait(), "task x: a")
+
+	want := []string{
+		"start x 1",
+		"finish x 1 task x: a",
+		"start  2",
+		"finish  2 EOF",
+	}
+	require.Equal(t, want, first.events)
+	require.Equal(t, want, second.events)
+}
+
+func TestGroupAddContext(t *testing.T) {
+	var (
+		g    errgroup.Group
+		have context.Context
+	)
+	g.AddContext(func(ctx context.Context) error {
+		have = ctx
+		return errA
+	})
+
+	require.ErrorIs(t, g.Wait(), errA)
+	require.Equal(t, context.Background(), have)
+}
+
 func TestWithoutContext(t *testing.T) {
 	var (
 		err = errors.New("foo")
@@ -151,3 +1656,25 @@ func TestWithoutContext(t *testing.T) {
 
 	require.ErrorIs(t, fnB(), fnA())
 }
+
+func BenchmarkGroupErrors(b *testing.B) {
+	opts := map[string][]errgroup.Option{
+		"all":     nil,
+		"first":   {errgroup.WithFirstOnly()},
+		"max":     {errgroup.WithMaxErrors(10)},
+		"ordered": {errgroup.WithOrderedErrors()},
+	}
+
+	for name, opts := range opts {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				g := errgroup.New(append(opts, errgroup.WithWorkers(8))...)
+				for j := 0; j < 1000; j++ {
+					g.Add(failingTask)
+				}
+				_errSink = g.Wait()
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errgroup
+
+import (
+	"context"
+	"strconv"
+)
+
+// An ItemError associates an error with the index of the item for which it
+// was returned (see ForEach and Map).
+type ItemError struct {
+	// Err is the error returned for the item.
+	Err error
+	// Index is the index of the item.
+	Index int
+}
+
+// Error returns the item error message.
+func (e *ItemError) Error() string {
+	return "item " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned for the item.
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// ForEach calls fn concurrently for each of the given items, and returns an
+// error joining all non-nil errors returned by fn, each wrapped in an
+// *ItemError identifying its item, in item order. The Group executing fn is
+// configured with the given options, e.g. WithLimit to bound the number of
+// concurrent calls. fn receives ctx, or a context derived from ctx if the
+// Group was configured using WithCancelOnError.
+func ForEach[T any](
+	ctx context.Context,
+	items []T,
+	fn func(context.Context, T) error,
+	opts ...Option,
+) error {
+	g := newItemGroup(ctx, opts)
+	for i, item := range items {
+		i, item := i, item
+		g.AddContext(func(ctx context.Context) error {
+			return itemError(i, fn(ctx, item))
+		})
+	}
+	return g.Wait()
+}
+
+// Map calls fn concurrently for each of the given items, and returns the
+// results in item order, along with an error joining all non-nil errors
+// returned by fn as described by ForEach. The result for an item for which fn
+// returns an error is included as returned.
+func Map[T, R any](
+	ctx context.Context,
+	items []T,
+	fn func(context.Context, T) (R, error),
+	opts ...Option,
+) ([]R, error) {
+	var (
+		g       = newItemGroup(ctx, opts)
+		results = make([]R, len(items))
+	)
+
+	for i, item := range items {
+		i, item := i, item
+		g.AddContext(func(ctx context.Context) (err error) {
+			results[i], err = fn(ctx, item)
+			return itemError(i, err)
+		})
+	}
+
+	return results, g.Wait()
+}
+
+func newItemGroup(ctx context.Context, opts []Option) *Group {
+	g := newGroup(ctx, opts)
+	g.options.OrderedErrors = true
+	return g
+}
+
+func itemError(index int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ItemError{
+		Err:   err,
+		Index: index,
+	}
+}
@@ -0,0 +1,102 @@
+package errgroup_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/errgroup"
+)
+
+func TestForEach(t *testing.T) {
+	var (
+		items = []int{1, 2, 3, 4}
+		sum   atomic.Int64
+	)
+
+	err := errgroup.ForEach(
+		context.Background(),
+		items,
+		func(_ context.Context, item int) error {
+			sum.Add(int64(item))
+			if item%2 == 0 {
+				time.Sleep(time.Duration(10-item) * time.Millisecond)
+				return errA
+			}
+			return nil
+		},
+	)
+	require.EqualError(t, err, "item 1: a\nitem 3: a")
+	require.Equal(t, int64(10), sum.Load())
+
+	itemErr, ok := errors.AsType[*errgroup.ItemError](err)
+	require.True(t, ok)
+	require.Equal(t, 1, itemErr.Index)
+	require.ErrorIs(t, itemErr, errA)
+}
+
+func TestForEach_Limit(t *testing.T) {
+	var active, peak atomic.Int64
+
+	err := errgroup.ForEach(
+		context.Background(),
+		make([]struct{}, 10),
+		func(context.Context, struct{}) error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				prev := peak.Load()
+				if n <= prev || peak.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		},
+		errgroup.WithLimit(2),
+	)
+	require.NoError(t, err)
+	require.LessOrEqual(t, peak.Load(), int64(2))
+}
+
+func TestForEach_CancelOnError(t *testing.T) {
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "x")
+	err := errgroup.ForEach(
+		ctx,
+		[]int{0, 1},
+		func(ctx context.Context, item int) error {
+			switch {
+			case ctx.Value(ctxKey{}) != "x":
+				return errC
+			case item == 0:
+				return errA
+			default:
+				<-ctx.Done()
+				return nil
+			}
+		},
+		errgroup.WithCancelOnError(),
+	)
+	require.EqualError(t, err, "item 0: a")
+}
+
+func TestMap(t *testing.T) {
+	results, err := errgroup.Map(
+		context.Background(),
+		[]string{"a", "bb", "", "dddd"},
+		func(_ context.Context, item string) (int, error) {
+			if len(item) == 0 {
+				return -1, errB
+			}
+			return len(item), nil
+		},
+		errgroup.WithLimit(1),
+	)
+	require.Equal(t, []int{1, 2, -1, 4}, results)
+	require.EqualError(t, err, "item 2: b")
+}
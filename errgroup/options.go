@@ -1,10 +1,78 @@
 package errgroup
 
+import (
+	"time"
+
+	"go.mway.dev/errors"
+)
+
 // Options are used to configure a Group.
 type Options struct {
+	// Join combines all non-nil errors returned by functions executed by a
+	// Group into the single error returned by Group.Wait. If nil, errors.Join
+	// is used.
+	Join func(errs ...error) error
+	// ErrorTransform, if not nil, is applied to each non-nil error returned
+	// by a function executed by a Group that is not ignored, before the
+	// error is recorded. If ErrorTransform returns nil, the error is
+	// discarded.
+	ErrorTransform func(error) error
+	// OnError, if not nil, is called with each error recorded by a Group as
+	// soon as it is recorded.
+	OnError func(error)
+	// PanicHandler, if not nil, is called with the value and stack of each
+	// panic recovered in a function executed by a Group, and returns the
+	// error recorded for the function in place of an *errors.PanicError.
+	// Setting PanicHandler implies PanicRecovery.
+	PanicHandler func(recovered any, stack []byte) error
+	// RetryBackoff, if not nil, returns the delay before each retry of a
+	// failed function (see RetryAttempts), given the retry's 1-based attempt
+	// number. If nil, functions are retried immediately.
+	RetryBackoff func(attempt int) time.Duration
+	// RetryIf, if not nil, reports whether a failed function should be
+	// retried (see RetryAttempts). If nil, all errors that are not ignored
+	// are retried.
+	RetryIf func(error) bool
+	// WrapMessage, if not empty, is the message with which the error returned
+	// by Group.Wait is wrapped (see errors.Wrap).
+	WrapMessage string
 	// IgnoredErrors is used to filter out unhelpful or immaterial errors,
 	// such as io.EOF.
 	IgnoredErrors []error
+	// IgnoreFuncs are used to filter out classes of errors; an error is
+	// ignored if any of the functions returns true for it.
+	IgnoreFuncs []func(error) bool
+	// Observers are notified as a Group executes functions.
+	Observers []Observer
+	// SampleRate, if between 0 and 1 exclusive, is the probability with
+	// which a Group records each error after the first; errors that are not
+	// sampled are counted separately from omitted errors.
+	SampleRate float64
+	// TaskTimeout, if positive, is the maximum duration of each function
+	// passed to Group.AddContext; each function's context is canceled once
+	// its timeout elapses.
+	TaskTimeout time.Duration
+	// Limit is the maximum number of functions that a Group executes
+	// concurrently. If Limit is not positive, the number of concurrently
+	// executing functions is unbounded. Limit has no effect if Workers is
+	// positive.
+	Limit int
+	// MaxErrors, if positive, is the maximum number of errors that a Group
+	// records; further errors are counted but otherwise discarded.
+	MaxErrors int
+	// FirstN, if positive, is the number of errors that a Group records, in
+	// the order in which they are returned; further errors are discarded.
+	FirstN int
+	// RetryAttempts, if greater than one, is the maximum number of times that
+	// a Group executes each function that fails before recording its error.
+	RetryAttempts int
+	// MaxWeight, if positive, is the total weight of the functions passed to
+	// Group.AddWeighted that a Group executes concurrently.
+	MaxWeight int64
+	// Workers, if positive, is the number of long-lived goroutines with which
+	// a Group executes functions, rather than starting a goroutine for each
+	// function.
+	Workers int
 	// FirstOnly controls whether only the first non-nil error encountered will
 	// be returned, or if all errors will be appended in a chain and returned.
 	FirstOnly bool
@@ -13,15 +81,63 @@ type Options struct {
 	// executed in parallel in a background goroutine. Note that if Inline
 	// is true, Group.Add becomes a blocking call.
 	Inline bool
+	// CancelOnError controls whether the Group's context, which is passed to
+	// functions given to Group.AddContext, is canceled as soon as any
+	// function returns a non-nil error that is not ignored.
+	CancelOnError bool
+	// StopOnFatal controls whether functions that fail with a fatal error
+	// (see errors.Fatal) are not retried, and whether the Group's context is
+	// canceled as soon as any function returns a fatal error that is not
+	// ignored, even if CancelOnError is false.
+	StopOnFatal bool
+	// PanicRecovery controls whether panics in functions executed by a Group
+	// are recovered and recorded as *errors.PanicError values, rather than
+	// crashing the process.
+	PanicRecovery bool
+	// OrderedErrors controls whether the errors returned by Group.Wait are
+	// joined in the order in which their functions were submitted to the
+	// Group, rather than the order in which they were recorded.
+	OrderedErrors bool
+	// TaskNames controls whether errors returned by functions executed by a
+	// Group are wrapped in a *TaskError named after the function, as
+	// reported by the runtime.
+	TaskNames bool
+	// TaskTiming controls whether errors returned by functions executed by a
+	// Group are wrapped in a *TimedError recording the function's execution
+	// time, and whether function durations are tracked in Group.Stats.
+	TaskTiming bool
 }
 
 // DefaultOptions returns a new Options with sane defaults. Using default
 // Options verbatim is functionally equivalent to using a zero-value Group.
 func DefaultOptions() Options {
 	return Options{
-		IgnoredErrors: nil,
-		FirstOnly:     false,
-		Inline:        false,
+		IgnoredErrors:  nil,
+		IgnoreFuncs:    nil,
+		Observers:      nil,
+		WrapMessage:    "",
+		FirstOnly:      false,
+		Inline:         false,
+		CancelOnError:  false,
+		StopOnFatal:    false,
+		Limit:          0,
+		MaxErrors:      0,
+		PanicRecovery:  false,
+		TaskTimeout:    0,
+		OrderedErrors:  false,
+		TaskNames:      false,
+		TaskTiming:     false,
+		Join:           errors.Join,
+		ErrorTransform: nil,
+		OnError:        nil,
+		PanicHandler:   nil,
+		RetryAttempts:  0,
+		RetryBackoff:   nil,
+		RetryIf:        nil,
+		Workers:        0,
+		MaxWeight:      0,
+		FirstN:         0,
+		SampleRate:     0,
 	}
 }
 
@@ -37,10 +153,57 @@ func (o Options) With(opts ...Option) Options {
 func (o Options) apply(opts *Options) {
 	opts.FirstOnly = o.FirstOnly
 	opts.Inline = o.Inline
+	opts.CancelOnError = o.CancelOnError
+	opts.StopOnFatal = o.StopOnFatal
+	opts.Limit = o.Limit
+	opts.MaxErrors = o.MaxErrors
+	opts.PanicRecovery = o.PanicRecovery
+	opts.TaskTimeout = o.TaskTimeout
+	opts.OrderedErrors = o.OrderedErrors
+	opts.TaskNames = o.TaskNames
+	opts.TaskTiming = o.TaskTiming
+	opts.WrapMessage = o.WrapMessage
+	opts.RetryAttempts = o.RetryAttempts
+	opts.Workers = o.Workers
+	opts.MaxWeight = o.MaxWeight
+	opts.FirstN = o.FirstN
+	opts.SampleRate = o.SampleRate
+
+	if o.Join != nil {
+		opts.Join = o.Join
+	}
+
+	if o.ErrorTransform != nil {
+		opts.ErrorTransform = o.ErrorTransform
+	}
+
+	if o.OnError != nil {
+		opts.OnError = o.OnError
+	}
+
+	if o.PanicHandler != nil {
+		opts.PanicHandler = o.PanicHandler
+	}
+
+	if o.RetryBackoff != nil {
+		opts.RetryBackoff = o.RetryBackoff
+	}
+
+	if o.RetryIf != nil {
+		opts.RetryIf = o.RetryIf
+	}
 
 	if o.IgnoredErrors != nil {
 		opts.IgnoredErrors = append(opts.IgnoredErrors, o.IgnoredErrors...)
 	}
+
+	if o.IgnoreFuncs != nil {
+		opts.IgnoreFuncs = append(opts.IgnoreFuncs, o.IgnoreFuncs...)
+	}
+
+	if o.Observers != nil {
+		opts.Observers = append(opts.Observers, o.Observers...)
+	}
 }
 
 // An Option configures a Group.
@@ -54,6 +217,29 @@ func (f optionFunc) apply(o *Options) {
 	f(o)
 }
 
+// WithCancelOnError returns an Option that configures a Group to cancel its
+// context as soon as any function returns a non-nil error that is not
+// ignored, so that functions passed to Group.AddContext can stop early. Groups
+// created with WithContext always cancel on error.
+func WithCancelOnError() Option {
+	return optionFunc(func(o *Options) {
+		o.CancelOnError = true
+	})
+}
+
+// WithStopOnFatal returns an Option that configures a Group to treat errors
+// marked as fatal (see errors.Fatal and errors.FatalIf) as a signal to stop:
+// functions that fail with a fatal error are not retried (see WithRetry), and
+// the Group's context is canceled as soon as any function returns a fatal
+// error that is not ignored, so that functions passed to Group.AddContext can
+// shut down cleanly. Other errors do not cancel the Group's context unless
+// WithCancelOnError is also used.
+func WithStopOnFatal() Option {
+	return optionFunc(func(o *Options) {
+		o.StopOnFatal = true
+	})
+}
+
 // WithFirstOnly returns an Option that configures a Group to return the first
 // encountered error verbatim. Subsequently returned errors will be ignored.
 func WithFirstOnly() Option {
@@ -62,6 +248,32 @@ func WithFirstOnly() Option {
 	})
 }
 
+// WithFirstN returns an Option that configures a Group to record only the
+// first n errors returned by its functions, in the order in which they are
+// returned, and to discard the rest, as a middle ground between WithFirstOnly
+// and recording every error. Unlike with WithMaxErrors, discarded errors are
+// not reported by Group.Wait. WithFirstN(1) is equivalent to WithFirstOnly().
+// If n is not positive, all errors are recorded.
+func WithFirstN(n int) Option {
+	return optionFunc(func(o *Options) {
+		o.FirstN = n
+	})
+}
+
+// WithSampling returns an Option that configures a Group to record each error
+// after the first with probability rate, so that executing many failing
+// functions does not retain every error while still surfacing a
+// representative sample. Errors that are not sampled are counted in
+// Group.Stats as failures, and Group.Wait joins a *SampledError reporting the
+// number of unsampled errors with the recorded ones; unlike errors omitted
+// because of WithMaxErrors, they are not reported by a *TruncatedError. If
+// rate is not between 0 and 1 exclusive, all errors are recorded.
+func WithSampling(rate float64) Option {
+	return optionFunc(func(o *Options) {
+		o.SampleRate = rate
+	})
+}
+
 // WithIgnoredErrors returns an Option that configures a Group to ignore errors
 // that contain any of the given errors in their error chains.
 func WithIgnoredErrors(errs ...error) Option {
@@ -73,6 +285,21 @@ func WithIgnoredErrors(errs ...error) Option {
 	})
 }
 
+// WithIgnoreFunc returns an Option that configures a Group to ignore errors for
+// which fn returns true, e.g. to ignore every *net.DNSError without
+// enumerating specific errors. If fn is nil, WithIgnoreFunc has no effect.
+func WithIgnoreFunc(fn func(error) bool) Option {
+	return optionFunc(func(o *Options) {
+		if fn == nil {
+			return
+		}
+
+		tmp := make([]func(error) bool, 0, len(o.IgnoreFuncs)+1)
+		tmp = append(tmp, o.IgnoreFuncs...)
+		o.IgnoreFuncs = append(tmp, fn)
+	})
+}
+
 // WithInline returns an Option that configures a Group to execute all
 // functions provided to Group.Add inline and serially within the calling
 // goroutine. Note that this will make Group.Add a blocking call.
@@ -81,3 +308,226 @@ func WithInline() Option {
 		o.Inline = true
 	})
 }
+
+// WithLimit returns an Option that configures a Group to execute at most n
+// functions concurrently; once the limit is reached, Group.Add blocks until a
+// running function returns. If n is not positive, the number of concurrently
+// executing functions is unbounded.
+func WithLimit(n int) Option {
+	return optionFunc(func(o *Options) {
+		o.Limit = n
+	})
+}
+
+// WithPanicRecovery returns an Option that configures a Group to recover
+// panics in the functions it executes. A recovered panic is recorded as an
+// *errors.PanicError, which carries the panicking goroutine's stack, and is
+// returned by Group.Wait like any other error.
+func WithPanicRecovery() Option {
+	return optionFunc(func(o *Options) {
+		o.PanicRecovery = true
+	})
+}
+
+// WithPanicHandler returns an Option that configures a Group to recover
+// panics in the functions it executes, as WithPanicRecovery does, but to call
+// fn with each recovered value and the panicking goroutine's stack to decide
+// what happens next. The error returned by fn is recorded in place of the
+// panic, as if returned by the function; if fn returns nil, the function is
+// considered to have succeeded. fn may also re-panic, e.g. after cleaning up
+// or writing a crash dump. If fn is nil, WithPanicHandler has no effect.
+func WithPanicHandler(fn func(recovered any, stack []byte) error) Option {
+	return optionFunc(func(o *Options) {
+		if fn != nil {
+			o.PanicHandler = fn
+		}
+	})
+}
+
+// WithTaskTimeout returns an Option that configures a Group to apply a
+// timeout of d to each function passed to Group.AddContext. A function that
+// returns an error after exceeding its timeout is recorded as a
+// *TaskTimeoutError that identifies the function. If d is not positive, no
+// timeout is applied.
+func WithTaskTimeout(d time.Duration) Option {
+	return optionFunc(func(o *Options) {
+		o.TaskTimeout = d
+	})
+}
+
+// WithOrderedErrors returns an Option that configures a Group to join the
+// errors returned by Group.Wait in the order in which their functions were
+// submitted to the Group, rather than the order in which they finished, so
+// that the aggregate error is stable across runs.
+func WithOrderedErrors() Option {
+	return optionFunc(func(o *Options) {
+		o.OrderedErrors = true
+	})
+}
+
+// WithTaskNames returns an Option that configures a Group to wrap errors
+// returned by the functions it executes in a *TaskError named after the
+// function, as reported by the runtime (e.g. "task main.fetch.func1: ..."). To
+// choose a name explicitly, use Group.AddNamed.
+func WithTaskNames() Option {
+	return optionFunc(func(o *Options) {
+		o.TaskNames = true
+	})
+}
+
+// WithTaskTiming returns an Option that configures a Group to time the
+// functions it executes, wrapping each error they return in a *TimedError
+// that records when the function started and how long it ran (e.g. "task
+// failed after 4.2s: ..."), so that slow failures can be told apart from fast
+// ones. The durations of all functions are also tracked in Group.Stats.
+func WithTaskTiming() Option {
+	return optionFunc(func(o *Options) {
+		o.TaskTiming = true
+	})
+}
+
+// WithWrapMessage returns an Option that configures a Group to wrap the error
+// returned by Group.Wait, WaitContext, WaitTimeout, and Shutdown with msg, as
+// errors.Wrap would (e.g. "msg: <error>"), to annotate the Group's aggregate
+// error without checking it at every call site. If msg is empty, the error is
+// not wrapped.
+func WithWrapMessage(msg string) Option {
+	return optionFunc(func(o *Options) {
+		o.WrapMessage = msg
+	})
+}
+
+// WithErrorTransform returns an Option that configures a Group to apply fn to
+// each non-nil error returned by the functions it executes, e.g. to wrap the
+// error with additional context or to redact it, before the error is
+// recorded. Errors are matched against ignored errors (see
+// WithIgnoredErrors) before being transformed. If fn returns nil, the error is
+// discarded. If WithErrorTransform is used more than once, the transforms are
+// applied in the order they were given.
+func WithErrorTransform(fn func(error) error) Option {
+	return optionFunc(func(o *Options) {
+		switch prev := o.ErrorTransform; {
+		case fn == nil:
+		case prev == nil:
+			o.ErrorTransform = fn
+		default:
+			o.ErrorTransform = func(err error) error {
+				if err = prev(err); err == nil {
+					return nil
+				}
+				return fn(err)
+			}
+		}
+	})
+}
+
+// WithOnError returns an Option that configures a Group to call fn with each
+// error it records, as soon as the error is recorded, e.g. to log the error
+// or to emit metrics. Ignored and discarded errors (see WithIgnoredErrors,
+// WithErrorTransform, and WithFirstOnly) are not passed to fn. fn is called
+// from the goroutine that executed the failing function, without holding any
+// of the Group's locks. If WithOnError is used more than once, each fn is
+// called in the order they were given.
+func WithOnError(fn func(error)) Option {
+	return optionFunc(func(o *Options) {
+		switch prev := o.OnError; {
+		case fn == nil:
+		case prev == nil:
+			o.OnError = fn
+		default:
+			o.OnError = func(err error) {
+				prev(err)
+				fn(err)
+			}
+		}
+	})
+}
+
+// WithMaxErrors returns an Option that configures a Group to record at most n
+// errors, so that a Group executing many failing functions does not retain
+// every error. Once n errors have been recorded, further errors are only
+// counted, and Group.Wait joins a *TruncatedError reporting the number of
+// omitted errors with the recorded ones. If n is not positive, all errors are
+// recorded.
+func WithMaxErrors(n int) Option {
+	return optionFunc(func(o *Options) {
+		o.MaxErrors = n
+	})
+}
+
+// WithJoin returns an Option that configures a Group to combine all non-nil
+// errors returned by its functions using fn, e.g. to preserve the formatting
+// of a particular multi-error implementation. If fn is nil, errors.Join is
+// used.
+func WithJoin(fn func(errs ...error) error) Option {
+	return optionFunc(func(o *Options) {
+		if fn == nil {
+			fn = errors.Join
+		}
+		o.Join = fn
+	})
+}
+
+// WithRetry returns an Option that configures a Group to execute each function
+// that fails up to attempts times in total, so that transient failures are
+// retried by the Group rather than within each function. Before each retry,
+// the Group waits for the duration returned by backoff for that retry's
+// 1-based attempt number, or until the Group's context is canceled, in which
+// case the function is not retried. Only errors for which retryIf returns
+// true are retried; ignored errors (see WithIgnoredErrors) and panics are
+// never retried. If backoff is nil, functions are retried immediately; if
+// retryIf is nil, all errors are retried. If attempts is less than two,
+// functions are not retried.
+func WithRetry(
+	attempts int,
+	backoff func(attempt int) time.Duration,
+	retryIf func(error) bool,
+) Option {
+	return optionFunc(func(o *Options) {
+		o.RetryAttempts = attempts
+		o.RetryBackoff = backoff
+		o.RetryIf = retryIf
+	})
+}
+
+// WithWorkers returns an Option that configures a Group to execute functions
+// using a pool of n long-lived worker goroutines fed by a queue, rather than
+// starting a goroutine for each function, so that submitting many small
+// functions does not create as many goroutines. The workers are started when
+// the first function is submitted, and stop when Wait returns. Once all
+// workers are busy and the queue, which holds up to n functions, is full,
+// Group.Add blocks and Group.TryAdd fails. WithWorkers supersedes WithLimit.
+// If n is not positive, a goroutine is started for each function.
+func WithWorkers(n int) Option {
+	return optionFunc(func(o *Options) {
+		o.Workers = n
+	})
+}
+
+// WithMaxWeight returns an Option that configures a Group to bound the total
+// weight of the functions passed to Group.AddWeighted that it executes
+// concurrently to total, so that functions with different resource needs can
+// share a Group while respecting a single budget. If total is not positive,
+// weights are ignored.
+func WithMaxWeight(total int64) Option {
+	return optionFunc(func(o *Options) {
+		o.MaxWeight = total
+	})
+}
+
+// WithObserver returns an Option that configures a Group to notify o as it
+// executes functions, e.g. to record tracing spans or duration histograms
+// without instrumenting each function. If WithObserver is used more than once,
+// each Observer is notified in the order they were given. If o is nil,
+// WithObserver has no effect.
+func WithObserver(o Observer) Option {
+	return optionFunc(func(opts *Options) {
+		if o == nil {
+			return
+		}
+
+		tmp := make([]Observer, 0, len(opts.Observers)+1)
+		tmp = append(tmp, opts.Observers...)
+		opts.Observers = append(tmp, o)
+	})
+}
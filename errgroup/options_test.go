@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.mway.dev/errors/errgroup"
@@ -15,11 +16,32 @@ func TestOptionsWith(t *testing.T) {
 		previous = base.With(
 			errgroup.WithFirstOnly(),
 			errgroup.WithIgnoredErrors(context.Canceled),
+			errgroup.WithIgnoreFunc(func(error) bool { return false }),
+			errgroup.WithCancelOnError(),
+			errgroup.WithStopOnFatal(),
+			errgroup.WithLimit(2),
+			errgroup.WithPanicRecovery(),
+			errgroup.WithPanicHandler(func(any, []byte) error { return nil }),
+			errgroup.WithTaskTimeout(time.Second),
+			errgroup.WithTaskNames(),
+			errgroup.WithTaskTiming(),
+			errgroup.WithWrapMessage("flush"),
+			errgroup.WithMaxErrors(10),
+			errgroup.WithFirstN(5),
+			errgroup.WithSampling(0.5),
+			errgroup.WithOrderedErrors(),
+			errgroup.WithRetry(3, nil, nil),
+			errgroup.WithWorkers(4),
+			errgroup.WithMaxWeight(8),
+			errgroup.WithObserver(nil),
+			errgroup.WithObserver(&recordingObserver{}),
 		)
 		updated = previous.With(
 			errgroup.DefaultOptions().With(
 				errgroup.WithInline(),
 				errgroup.WithIgnoredErrors(io.EOF),
+				errgroup.WithIgnoreFunc(func(error) bool { return true }),
+				errgroup.WithObserver(&recordingObserver{}),
 			),
 		)
 	)
@@ -27,8 +49,62 @@ func TestOptionsWith(t *testing.T) {
 	require.True(t, previous.FirstOnly)
 	require.False(t, previous.Inline)
 	require.Len(t, previous.IgnoredErrors, 1)
+	require.Len(t, previous.IgnoreFuncs, 1)
+	require.True(t, previous.CancelOnError)
+	require.True(t, previous.StopOnFatal)
+	require.Equal(t, 2, previous.Limit)
+	require.True(t, previous.PanicRecovery)
+	require.NotNil(t, previous.PanicHandler)
+	require.Equal(t, time.Second, previous.TaskTimeout)
+	require.True(t, previous.TaskNames)
+	require.True(t, previous.TaskTiming)
+	require.Equal(t, "flush", previous.WrapMessage)
+	require.Equal(t, 10, previous.MaxErrors)
+	require.Equal(t, 5, previous.FirstN)
+	require.Equal(t, 0.5, previous.SampleRate)
+	require.True(t, previous.OrderedErrors)
+	require.Equal(t, 3, previous.RetryAttempts)
+	require.Equal(t, 4, previous.Workers)
+	require.Equal(t, int64(8), previous.MaxWeight)
+	require.Len(t, previous.Observers, 1)
 
 	require.False(t, updated.FirstOnly)
 	require.True(t, updated.Inline)
 	require.Len(t, updated.IgnoredErrors, 2)
+	require.Len(t, updated.IgnoreFuncs, 2)
+	require.False(t, updated.CancelOnError)
+	require.False(t, updated.StopOnFatal)
+	require.Zero(t, updated.Limit)
+	require.False(t, updated.PanicRecovery)
+	require.Zero(t, updated.TaskTimeout)
+	require.False(t, updated.TaskNames)
+	require.False(t, updated.TaskTiming)
+	require.Empty(t, updated.WrapMessage)
+	require.Zero(t, updated.MaxErrors)
+	require.Zero(t, updated.FirstN)
+	require.Zero(t, updated.SampleRate)
+	require.False(t, updated.OrderedErrors)
+	require.Zero(t, updated.RetryAttempts)
+	require.Zero(t, updated.Workers)
+	require.Zero(t, updated.MaxWeight)
+	require.Len(t, updated.Observers, 2)
+}
+
+func TestOptionsWithJoin(t *testing.T) {
+	var (
+		called bool
+		join   = func(errs ...error) error {
+			called = true
+			return errs[0]
+		}
+		opts = errgroup.DefaultOptions().With(errgroup.WithJoin(join))
+	)
+
+	require.NotNil(t, errgroup.DefaultOptions().Join)
+	require.NoError(t, opts.Join(nil))
+	require.True(t, called)
+
+	opts = opts.With(errgroup.WithJoin(nil))
+	require.NotNil(t, opts.Join)
+	require.Error(t, opts.Join(io.EOF))
 }
@@ -0,0 +1,146 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errgroup
+
+import (
+	"context"
+	"sync"
+)
+
+// A Pipeline executes stages connected by channels, such as a Source feeding
+// one or more Stages that feed a Sink, as functions of a single Group. The
+// first error returned by any stage cancels the Pipeline's context, which
+// stops every stage, and all errors are joined by Wait.
+type Pipeline struct {
+	g   *Group
+	ctx context.Context
+}
+
+// NewPipeline creates a new Pipeline with the given options, along with the
+// context passed to its stages, which is derived from ctx and canceled as
+// described by WithContext. Options that control concurrency, i.e. WithInline,
+// WithLimit, WithWorkers, and WithMaxWeight, are ignored, because each stage
+// determines its own concurrency. WithRetry is also ignored, because a stage
+// cannot be restarted once it has consumed or closed its channels.
+func NewPipeline(ctx context.Context, opts ...Option) (*Pipeline, context.Context) {
+	g, ctx := WithContext(ctx, opts...)
+	g.options.Inline = false
+	g.options.Workers = 0
+	g.options.MaxWeight = 0
+	g.options.RetryAttempts = 0
+	g.SetLimit(0)
+
+	return &Pipeline{
+		g:   g,
+		ctx: ctx,
+	}, ctx
+}
+
+// Wait blocks until all of the Pipeline's stages have returned, and returns
+// their errors as Group.Wait does.
+func (p *Pipeline) Wait() error {
+	return p.g.Wait()
+}
+
+// Source adds a stage to p that calls fn to produce values, and returns the
+// channel to which fn sends them. The channel is closed when fn returns. fn
+// should stop sending once its context is done, e.g. using a select.
+func Source[T any](p *Pipeline, fn func(context.Context, chan<- T) error) <-chan T {
+	out := make(chan T)
+	p.g.AddContext(func(ctx context.Context) error {
+		defer close(out)
+		return fn(ctx, out)
+	})
+	return out
+}
+
+// Stage adds a stage to p that calls fn with each value received from in, using
+// the given number of concurrent workers, and returns the channel to which the
+// results are sent. Results are not ordered when using more than one worker.
+// The channel is closed once in is closed and all workers have returned, or
+// once the Pipeline's context is done. If workers is not positive, one worker
+// is used.
+func Stage[In, Out any](
+	p *Pipeline,
+	in <-chan In,
+	workers int,
+	fn func(context.Context, In) (Out, error),
+) <-chan Out {
+	var (
+		out = make(chan Out)
+		wg  sync.WaitGroup
+	)
+
+	workers = max(workers, 1)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		p.g.AddContext(func(ctx context.Context) error {
+			defer wg.Done()
+			return consume(ctx, in, func(v In) error {
+				res, err := fn(ctx, v)
+				if err != nil {
+					return err
+				}
+
+				select {
+				case out <- res:
+				case <-ctx.Done():
+				}
+				return nil
+			})
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// Sink adds a stage to p that calls fn with each value received from in, until
+// in is closed or the Pipeline's context is done.
+func Sink[T any](p *Pipeline, in <-chan T, fn func(context.Context, T) error) {
+	p.g.AddContext(func(ctx context.Context) error {
+		return consume(ctx, in, func(v T) error {
+			return fn(ctx, v)
+		})
+	})
+}
+
+// consume calls fn with each value received from in, until in is closed, ctx
+// is done, or fn returns an error.
+func consume[T any](ctx context.Context, in <-chan T, fn func(T) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case v, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+	}
+}
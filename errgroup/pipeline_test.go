@@ -0,0 +1,107 @@
+package errgroup_test
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors/errgroup"
+)
+
+func TestPipeline(t *testing.T) {
+	var (
+		p, _ = errgroup.NewPipeline(context.Background(), errgroup.WithLimit(1))
+		mu   sync.Mutex
+		have []string
+	)
+
+	nums := errgroup.Source(p, func(ctx context.Context, out chan<- int) error {
+		for i := 1; i <= 10; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+	squares := errgroup.Stage(p, nums, 3, func(_ context.Context, n int) (int, error) {
+		return n * n, nil
+	})
+	strs := errgroup.Stage(p, squares, 0, func(_ context.Context, n int) (string, error) {
+		return strconv.Itoa(n), nil
+	})
+	errgroup.Sink(p, strs, func(_ context.Context, s string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		have = append(have, s)
+		return nil
+	})
+
+	require.NoError(t, p.Wait())
+	sort.Strings(have)
+	require.Equal(
+		t,
+		[]string{"1", "100", "16", "25", "36", "4", "49", "64", "81", "9"},
+		have,
+	)
+}
+
+func TestPipeline_Error(t *testing.T) {
+	p, ctx := errgroup.NewPipeline(context.Background())
+
+	nums := errgroup.Source(p, func(ctx context.Context, out chan<- int) error {
+		for i := 0; ; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+	halves := errgroup.Stage(p, nums, 2, func(_ context.Context, n int) (int, error) {
+		if n == 5 {
+			return 0, errA
+		}
+		return n / 2, nil
+	})
+	errgroup.Sink(p, halves, func(context.Context, int) error {
+		return nil
+	})
+
+	require.Equal(t, errA, p.Wait())
+	require.Error(t, ctx.Err())
+}
+
+func TestPipeline_Retry(t *testing.T) {
+	var (
+		p, _          = errgroup.NewPipeline(context.Background(), errgroup.WithRetry(3, nil, nil))
+		sources, sink atomic.Int64
+		stages        atomic.Int64
+	)
+
+	nums := errgroup.Source(p, func(_ context.Context, out chan<- int) error {
+		sources.Add(1)
+		out <- 1
+		return errA
+	})
+	squares := errgroup.Stage(p, nums, 2, func(context.Context, int) (int, error) {
+		stages.Add(1)
+		return 0, errB
+	})
+	errgroup.Sink(p, squares, func(context.Context, int) error {
+		sink.Add(1)
+		return nil
+	})
+
+	err := p.Wait()
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.Equal(t, int64(1), sources.Load())
+	require.Equal(t, int64(1), stages.Load())
+	require.Zero(t, sink.Load())
+}
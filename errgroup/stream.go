@@ -0,0 +1,91 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errgroup
+
+import "sync"
+
+// An errStream delivers errors to a channel in the order they are pushed,
+// without ever blocking the pusher; errors are queued until the channel's
+// receiver is ready for them.
+type errStream struct {
+	ch     chan error
+	notify chan struct{}
+	queue  []error
+	mu     sync.Mutex
+	closed bool
+}
+
+func newErrStream(errs []error) *errStream {
+	s := &errStream{
+		ch:     make(chan error),
+		notify: make(chan struct{}, 1),
+		queue:  append([]error(nil), errs...),
+	}
+	go s.forward()
+	return s
+}
+
+// push queues err for delivery.
+func (s *errStream) push(err error) {
+	s.mu.Lock()
+	s.queue = append(s.queue, err)
+	s.mu.Unlock()
+	s.wake()
+}
+
+// close closes the channel once all queued errors have been delivered.
+func (s *errStream) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.wake()
+}
+
+func (s *errStream) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *errStream) forward() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			closed := s.closed
+			s.mu.Unlock()
+
+			if closed {
+				close(s.ch)
+				return
+			}
+			<-s.notify
+			continue
+		}
+
+		err := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		s.ch <- err
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errgroup
+
+import "sync"
+
+// A weightedSem is a weighted semaphore that admits acquirers in FIFO order,
+// so that heavy acquirers are not starved by light ones.
+type weightedSem struct {
+	waiters []weightedWaiter
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+}
+
+type weightedWaiter struct {
+	ready chan struct{}
+	n     int64
+}
+
+func newWeightedSem(size int64) *weightedSem {
+	return &weightedSem{
+		size: size,
+	}
+}
+
+// acquire blocks until n can be acquired without exceeding the semaphore's
+// size and all earlier acquirers have been admitted.
+func (s *weightedSem) acquire(n int64) {
+	s.mu.Lock()
+	if len(s.waiters) == 0 && s.cur+n <= s.size {
+		s.cur += n
+		s.mu.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	s.waiters = append(s.waiters, weightedWaiter{
+		ready: ready,
+		n:     n,
+	})
+	s.mu.Unlock()
+
+	<-ready
+}
+
+// release releases n, admitting as many waiting acquirers as fit.
+func (s *weightedSem) release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	for len(s.waiters) > 0 {
+		next := s.waiters[0]
+		if s.cur+next.n > s.size {
+			break
+		}
+
+		s.cur += next.n
+		close(next.ready)
+		s.waiters[0] = weightedWaiter{}
+		s.waiters = s.waiters[1:]
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+//go:build !plan9
+
+package errors
+
+import (
+	"syscall"
+)
+
+// Errno returns the first syscall.Errno in err's chain, if any. Errno looks
+// through any wrapping layers, including *os.PathError, *os.SyscallError, and
+// *net.OpError.
+func Errno(err error) (syscall.Errno, bool) {
+	var errno syscall.Errno
+	if !As(err, &errno) {
+		return 0, false
+	}
+	return errno, true
+}
+
+// IsEAGAIN reports whether err's chain contains syscall.EAGAIN.
+func IsEAGAIN(err error) bool {
+	return isErrno(err, syscall.EAGAIN)
+}
+
+// IsECONNREFUSED reports whether err's chain contains syscall.ECONNREFUSED.
+func IsECONNREFUSED(err error) bool {
+	return isErrno(err, syscall.ECONNREFUSED)
+}
+
+// IsECONNRESET reports whether err's chain contains syscall.ECONNRESET.
+func IsECONNRESET(err error) bool {
+	return isErrno(err, syscall.ECONNRESET)
+}
+
+// IsENOSPC reports whether err's chain contains syscall.ENOSPC.
+func IsENOSPC(err error) bool {
+	return isErrno(err, syscall.ENOSPC)
+}
+
+// IsEPIPE reports whether err's chain contains syscall.EPIPE.
+func IsEPIPE(err error) bool {
+	return isErrno(err, syscall.EPIPE)
+}
+
+func isErrno(err error, target syscall.Errno) bool {
+	errno, ok := Errno(err)
+	return ok && errno == target
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+//go:build !plan9
+
+package errors_test
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestErrno(t *testing.T) {
+	cases := map[string]struct {
+		give      error
+		wantErrno syscall.Errno
+		wantOK    bool
+	}{
+		"nil": {
+			give:   nil,
+			wantOK: false,
+		},
+		"not errno": {
+			give:   errors.New("foo"),
+			wantOK: false,
+		},
+		"bare": {
+			give:      syscall.ENOSPC,
+			wantErrno: syscall.ENOSPC,
+			wantOK:    true,
+		},
+		"path error": {
+			give: errors.Wrap(&os.PathError{
+				Op:   "write",
+				Path: "/foo",
+				Err:  syscall.ENOSPC,
+			}, "flush"),
+			wantErrno: syscall.ENOSPC,
+			wantOK:    true,
+		},
+		"op error": {
+			give: &net.OpError{
+				Op:  "read",
+				Net: "tcp",
+				Err: os.NewSyscallError("read", syscall.ECONNRESET),
+			},
+			wantErrno: syscall.ECONNRESET,
+			wantOK:    true,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			errno, ok := errors.Errno(tt.give)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantErrno, errno)
+		})
+	}
+}
+
+func TestErrnoPredicates(t *testing.T) {
+	wrap := func(errno syscall.Errno) error {
+		return errors.Wrap(&net.OpError{
+			Op:  "dial",
+			Net: "tcp",
+			Err: os.NewSyscallError("connect", errno),
+		}, "wrapped")
+	}
+
+	cases := map[string]struct {
+		fn   func(error) bool
+		want syscall.Errno
+	}{
+		"EAGAIN":       {fn: errors.IsEAGAIN, want: syscall.EAGAIN},
+		"ECONNREFUSED": {fn: errors.IsECONNREFUSED, want: syscall.ECONNREFUSED},
+		"ECONNRESET":   {fn: errors.IsECONNRESET, want: syscall.ECONNRESET},
+		"ENOSPC":       {fn: errors.IsENOSPC, want: syscall.ENOSPC},
+		"EPIPE":        {fn: errors.IsEPIPE, want: syscall.EPIPE},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, tt.fn(wrap(tt.want)))
+			require.False(t, tt.fn(wrap(syscall.EINVAL)))
+			require.False(t, tt.fn(errors.New("foo")))
+			require.False(t, tt.fn(nil))
+		})
+	}
+}
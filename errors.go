@@ -22,15 +22,22 @@
 package errors
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // An ErrorFunc is a function that returns an error.
 type ErrorFunc = func() error
 
-// As is a proxy for the standard library's errors.As.
+// As is equivalent to the standard library's errors.As, except that the
+// traversal of err's chain is bounded by [MaxDepth] and stops at cycles.
 //
 // As finds the first error in err's chain that matches target, and if one is
 // found, sets target to that error value and returns true. Otherwise, it
@@ -50,10 +57,87 @@ type ErrorFunc = func() error
 // As panics if target is not a non-nil pointer to either a type that
 // implements error, or to any interface type.
 func As(err error, target any) bool {
-	return errors.As(err, target)
+	if err == nil {
+		return false
+	}
+	if target == nil {
+		panic("errors: target cannot be nil")
+	}
+
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		panic("errors: target must be a non-nil pointer")
+	}
+
+	targetType := val.Type().Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(_errorType) {
+		panic("errors: *target must be interface or implement error")
+	}
+
+	return findInChain(err, func(err error) bool {
+		if reflect.TypeOf(err).AssignableTo(targetType) {
+			val.Elem().Set(reflect.ValueOf(err))
+			return true
+		}
+		x, ok := err.(interface{ As(any) bool })
+		return ok && x.As(target)
+	}, newChainGuard())
+}
+
+// AsType finds the first error in err's chain that is of type T and returns
+// it. AsType is equivalent to calling [As] with a pointer to a T, but does not
+// use reflection to match errors, which makes it considerably cheaper in hot
+// paths.
+//
+// Like [As], AsType traverses the chain depth-first, following both
+// Unwrap() error and Unwrap() []error, and respects As(any) bool methods.
+// The traversal is bounded by [MaxDepth].
+func AsType[T error](err error) (T, bool) {
+	return asType[T](err, newChainGuard())
+}
+
+func asType[T error](err error, guard chainGuard) (T, bool) {
+	for err != nil && guard.visit(err) {
+		if lazy, ok := err.(*lazyError); ok {
+			err = lazy.get()
+			continue
+		}
+
+		if x, ok := err.(T); ok {
+			return x, true
+		}
+
+		if x, ok := err.(interface{ As(any) bool }); ok {
+			// The target only escapes to the heap if an As method is found.
+			var target T
+			if x.As(&target) {
+				return target, true
+			}
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			if guard.expand(err) {
+				for _, cause := range x.Unwrap() {
+					if target, ok := asType[T](cause, guard.branch()); ok {
+						return target, true
+					}
+				}
+			}
+			err = nil
+		default:
+			err = nil
+		}
+	}
+
+	var zero T
+	return zero, false
 }
 
-// Is is a proxy for the standard library's errors.Is.
+// Is is equivalent to the standard library's errors.Is, except that the
+// traversal of err's chain is bounded by [MaxDepth] and stops at cycles.
 //
 // Is reports whether any error in err's chain matches target.
 //
@@ -72,13 +156,46 @@ func As(err error, target any) bool {
 // example in the standard library. An Is method should only shallowly compare
 // err and the target and not call Unwrap on either.
 func Is(err error, target error) bool {
-	return errors.Is(err, target)
+	if err == nil || target == nil {
+		return err == target
+	}
+
+	comparable := reflect.TypeOf(target).Comparable()
+	return findInChain(err, func(err error) bool {
+		if comparable && err == target {
+			return true
+		}
+		x, ok := err.(interface{ Is(error) bool })
+		return ok && x.Is(target)
+	}, newChainGuard())
 }
 
 // Join combines all given errors into a single error. Any nil values are
-// discarded.
+// discarded. If all given errors are nil, Join returns nil.
+//
+// The resulting error's message consists of the messages of the joined
+// errors, separated by newlines. The message is computed the first time it is
+// needed and cached thereafter.
 func Join(errs ...error) error {
-	return errors.Join(errs...)
+	var n int
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	joined := &joinError{
+		errs: make([]error, 0, n),
+	}
+	for _, err := range errs {
+		if err != nil {
+			joined.errs = append(joined.errs, err)
+		}
+	}
+	return joined
 }
 
 // New is a proxy for the standard library's errors.New.
@@ -113,7 +230,9 @@ func Unwrap(err error) error {
 
 // Wrap returns a new error that wraps base, using msg as its error message.
 // Wrap produces an error of the format "msg: base" in order to provide the
-// consistent and coherent layering of errors.
+// consistent and coherent layering of errors; this format can be customized
+// with [SetWrapFormatter]. The message is not rendered until the error's
+// Error method is called.
 //
 // If base is nil, Wrap returns a nil error. If msg is an empty string, base
 // is returned verbatim.
@@ -124,16 +243,23 @@ func Wrap(base error, msg string) error {
 	case len(msg) == 0:
 		return base
 	default:
-		return fmt.Errorf("%s: %w", msg, base)
+		return &wrapError{
+			msg: msg,
+			err: base,
+		}
 	}
 }
 
 // Wrapf returns a new error that wraps base, using msg and args to format its
 // error message. Wrap produces an error of the format "msg: base", where msg
 // includes the interpolation of all sprintf placeholders and variables, in
-// order to provide the consistent and coherent layering of errors.
+// order to provide the consistent and coherent layering of errors; this
+// format can be customized with [SetWrapFormatter].
 //
-// Wrapf supports wrapping errors with the %w verb.
+// Wrapf supports wrapping errors with the %w verb. msg and args are formatted
+// when Wrapf is called, so args may be modified once Wrapf returns; like
+// [Wrap], the combination of the formatted message with base's message is
+// not rendered until the error's Error method is called.
 //
 // If base is nil, Wrapf returns a nil error. If msg is an empty string and
 // args is empty, base is returned verbatim.
@@ -143,15 +269,98 @@ func Wrapf(base error, msg string, args ...any) error {
 		return nil
 	case len(msg) == 0 && len(args) == 0:
 		return base
+	case !strings.Contains(msg, "%w"):
+		return &wrapError{
+			msg: fmt.Sprintf(msg, args...),
+			err: base,
+		}
 	default:
-		tmp := make([]any, len(args)+1)
-		copy(tmp, args)
-		tmp[len(tmp)-1] = base
+		var (
+			formatted = fmt.Errorf(msg, args...)
+			text      = formatted.Error()
+		)
 
-		return fmt.Errorf(msg+": %w", tmp...)
+		switch x := formatted.(type) {
+		case interface{ Unwrap() error }:
+			return &wrapErrors{
+				msg:  text,
+				errs: []error{x.Unwrap(), base},
+			}
+		case interface{ Unwrap() []error }:
+			causes := x.Unwrap()
+			errs := make([]error, 0, len(causes)+1)
+			errs = append(errs, causes...)
+			return &wrapErrors{
+				msg:  text,
+				errs: append(errs, base),
+			}
+		default:
+			return &wrapError{
+				msg: text,
+				err: base,
+			}
+		}
 	}
 }
 
+// WrapEach returns a new slice containing each of errs wrapped with [Wrap],
+// using the message returned by msgFn for its index and value, e.g. to
+// annotate each failure in a batch with the input that caused it. Nil errors
+// are left as nil, and msgFn is not called for them, so that the indexes of
+// the returned slice correspond to those of errs.
+func WrapEach(errs []error, msgFn func(i int, err error) string) []error {
+	if errs == nil {
+		return nil
+	}
+
+	wrapped := make([]error, len(errs))
+	for i, err := range errs {
+		if err != nil {
+			wrapped[i] = Wrap(err, msgFn(i, err))
+		}
+	}
+	return wrapped
+}
+
+// WrapEachJoin is like [WrapEach], but returns an error joining the wrapped
+// errors (see [Join]). If errs contains no non-nil errors, WrapEachJoin
+// returns nil.
+func WrapEachJoin(errs []error, msgFn func(i int, err error) string) error {
+	return Join(WrapEach(errs, msgFn)...)
+}
+
+// A WrapFormatter renders the message of an error created by [Wrap] or
+// [Wrapf], given the wrapping message and the message of the wrapped error.
+type WrapFormatter = func(msg string, base string) string
+
+var _wrapFormatter atomic.Pointer[WrapFormatter]
+
+// SetWrapFormatter sets the package-level [WrapFormatter] used by [Wrap] and
+// [Wrapf], and returns a function that restores the previous formatter. If fn
+// is nil, the default formatter, which produces "msg: base", is used.
+//
+// The formatter is applied each time a wrapped error's message is rendered,
+// so changing the formatter also affects errors that have already been
+// created.
+func SetWrapFormatter(fn WrapFormatter) (restore func()) {
+	var ptr *WrapFormatter
+	if fn != nil {
+		ptr = &fn
+	}
+
+	prev := _wrapFormatter.Swap(ptr)
+	return func() {
+		_wrapFormatter.Store(prev)
+	}
+}
+
+func formatWrap(msg string, base string) string {
+	if fn := _wrapFormatter.Load(); fn != nil {
+		return (*fn)(msg, base)
+	}
+	return msg + ": " + base
+}
+
 // JoinFuncs evaluates fns serially, joining all non-nil return values and
 // returning the resulting error. If fns is empty or if all fns return nil,
 // nil is returned; if only one error is produced, it is returned verbatim.
@@ -193,6 +402,53 @@ func JoinFuncs(fns ...ErrorFunc) error {
 	}
 }
 
+// FirstFunc evaluates fns serially, returning the first non-nil error produced
+// without evaluating the remaining fns, e.g. for validation chains in which
+// later checks are pointless after the first failure. Nil fns are skipped. If
+// no fn produces a non-nil error, nil is returned.
+func FirstFunc(fns ...ErrorFunc) error {
+	for _, fn := range fns {
+		if fn == nil {
+			continue
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JoinFuncsContext is like [JoinFuncs], but passes ctx to each of fns, and
+// stops evaluating fns once ctx is done, e.g. so that a cleanup sequence
+// respects a shutdown deadline. If ctx is done before all fns have been
+// evaluated, ctx.Err() is joined with the errors produced so far.
+func JoinFuncsContext(ctx context.Context, fns ...func(context.Context) error) error {
+	var errs []error
+	for _, fn := range fns {
+		if fn == nil {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return Join(errs...)
+	}
+}
+
 // AppendFunc evaluates fn and appends it to err. If either err or fn are nil,
 // the other is returned. If fn returns a nil error, err is returned.
 func AppendFunc(err error, fn ErrorFunc) error {
@@ -203,16 +459,16 @@ func AppendFunc(err error, fn ErrorFunc) error {
 		return fn()
 	default:
 		if e := fn(); e != nil {
-			return errors.Join(err, e)
+			return Join(err, e)
 		}
 		return err
 	}
 }
 
 // AppendFuncs evaluates fns serially, appending each return value to err. Nil
-// errors are ignored. If err and fns produce no non-nil errors, nil is
-// returned; if only one non-nil error is produced, it is returned verbatim.
-// Otherwise, the resulting non-nil errors are joined with [Join].
+// errors are ignored. If err and fns produce no non-nil errors, nil is returned; if only
+// one non-nil error is produced, it is returned verbatim. Otherwise, the
+// resulting non-nil errors are joined with [Join].
 func AppendFuncs(err error, fns ...ErrorFunc) error {
 	if len(fns) == 0 {
 		return err
@@ -255,28 +511,288 @@ func AppendFuncs(err error, fns ...ErrorFunc) error {
 
 // Lazy returns an error that will lazily evaluate fn; that is, fn will be
 // called at most once, and not until the resulting error would be used.
+//
+// Because the result of fn is not known until evaluation, the returned error
+// is always non-nil, and functions that merely collect or wrap errors, such as
+// [Join] or [AppendFuncs], treat it as any other non-nil error without
+// evaluating it. If fn returns nil, the lazy error reports an empty message,
+// does not match any target with [Is] or [As], and is omitted when its message
+// is rendered as part of a joined error; functions that inspect errors, such
+// as [Describe] or [CodeOf], treat it as nil. Use [LazyOrNil] to resolve a
+// lazy error to a true nil.
 func Lazy(fn ErrorFunc) error {
 	return &lazyError{
-		get: sync.OnceValue(fn),
+		fn: fn,
+	}
+}
+
+// LazyOrNil returns nil if err is nil or if err was created by [Lazy] and
+// evaluates to nil; otherwise, err is returned verbatim. Note that LazyOrNil
+// forces the evaluation of lazy errors.
+func LazyOrNil(err error) error {
+	if lazy, ok := err.(*lazyError); ok {
+		return LazyOrNil(lazy.get())
+	}
+	return err
+}
+
+// LazyEvaluated reports whether err is, or contains in its chain, an error
+// created by [Lazy] whose function has already been evaluated. Calling
+// LazyEvaluated does not cause evaluation.
+func LazyEvaluated(err error) bool {
+	_, ok := LazyResult(err)
+	return ok
+}
+
+// LazyResult returns the evaluated result of the first error created by
+// [Lazy] in err's chain, if that error has already been evaluated. If err does
+// not contain a lazy error, or if the lazy error has not yet been evaluated,
+// LazyResult returns false. Calling LazyResult does not cause evaluation.
+func LazyResult(err error) (error, bool) { //nolint:revive
+	var lazy *lazyError
+	if !As(err, &lazy) || !lazy.done.Load() {
+		return nil, false
+	}
+	return lazy.err, true
+}
+
+// LazyWrap returns a new error that wraps base, using the result of msgFn as
+// its error message. Unlike [Wrap], msgFn is not called until the resulting
+// error's message is actually needed, and is called at most once; this avoids
+// paying formatting costs for errors that are only ever inspected with [Is] or
+// [As], or that are otherwise discarded.
+//
+// If base is nil, LazyWrap returns a nil error. If msgFn is nil, base is
+// returned verbatim. If msgFn returns an empty string, the resulting error's
+// message is that of base.
+func LazyWrap(base error, msgFn func() string) error {
+	switch {
+	case base == nil:
+		return nil
+	case msgFn == nil:
+		return base
+	default:
+		return &lazyWrapError{
+			base: base,
+			msg:  sync.OnceValue(msgFn),
+		}
 	}
 }
 
 type lazyError struct {
-	get ErrorFunc
+	fn   ErrorFunc
+	err  error
+	once sync.Once
+	done atomic.Bool
+}
+
+func (e *lazyError) get() error {
+	e.once.Do(func() {
+		e.err = e.fn()
+		e.done.Store(true)
+	})
+	return e.err
+}
+
+func (e *lazyError) Unwrap() error {
+	return e.get()
+}
+
+func (e *lazyError) Error() string {
+	err := e.get()
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Format implements fmt.Formatter, forwarding to the evaluated error.
+func (e *lazyError) Format(s fmt.State, verb rune) {
+	switch err := e.get().(type) {
+	case nil:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), "")
+	case fmt.Formatter:
+		err.Format(s, verb)
+	default:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), err)
+	}
+}
+
+// MarshalJSON implements json.Marshaler, encoding the evaluated error as it
+// would be encoded directly.
+func (e *lazyError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.get())
+}
+
+// LogValue implements slog.LogValuer, forwarding to the evaluated error.
+func (e *lazyError) LogValue() slog.Value {
+	switch err := e.get().(type) {
+	case nil:
+		return slog.StringValue("")
+	case slog.LogValuer:
+		return err.LogValue()
+	default:
+		return slog.AnyValue(err)
+	}
+}
+
+// A messageCache caches the rendered message of an immutable error. Because
+// rendered messages depend on the current [WrapFormatter], a cached message is
+// discarded if the formatter has changed since it was rendered.
+type messageCache struct {
+	msg atomic.Pointer[cachedMessage]
+}
+
+type cachedMessage struct {
+	formatter *WrapFormatter
+	text      string
+}
+
+func (c *messageCache) get(render func() string) string {
+	formatter := _wrapFormatter.Load()
+	if msg := c.msg.Load(); msg != nil && msg.formatter == formatter {
+		return msg.text
+	}
+
+	text := render()
+	c.msg.Store(&cachedMessage{
+		formatter: formatter,
+		text:      text,
+	})
+	return text
 }
 
-func (e lazyError) As(target any) bool {
-	return errors.As(e.get(), target)
+type joinError struct {
+	cache messageCache
+	errs  []error
 }
 
-func (e lazyError) Is(target error) bool {
-	return errors.Is(e.get(), target)
+func (e *joinError) Unwrap() []error {
+	return e.errs
 }
 
-func (e lazyError) Unwrap() error {
-	return errors.Unwrap(e.get())
+func (e *joinError) Error() string {
+	return e.cache.get(e.render)
 }
 
-func (e lazyError) Error() string {
-	return e.get().Error()
+func (e *joinError) render() string {
+	if len(e.errs) == 1 {
+		return e.errs[0].Error()
+	}
+
+	var (
+		b     strings.Builder
+		first = true
+	)
+	for _, err := range e.errs {
+		if LazyOrNil(err) == nil {
+			continue
+		}
+		if !first {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+		first = false
+	}
+	return b.String()
+}
+
+// Format implements fmt.Formatter. The %+v verb renders the %+v renderings of
+// the joined errors, separated by newlines, so that annotations such as those
+// of [WithCaller] are rendered wherever they occur in the chain; all other
+// verbs render the message.
+func (e *joinError) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+		return
+	}
+
+	first := true
+	for _, err := range e.errs {
+		if LazyOrNil(err) == nil {
+			continue
+		}
+		if !first {
+			fmt.Fprint(s, "\n")
+		}
+		fmt.Fprintf(s, "%+v", err)
+		first = false
+	}
+}
+
+type wrapError struct {
+	cache messageCache
+	err   error
+	msg   string
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+func (e *wrapError) Error() string {
+	return e.cache.get(e.render)
+}
+
+func (e *wrapError) render() string {
+	return formatWrap(e.msg, e.err.Error())
+}
+
+// Format implements fmt.Formatter. The %+v verb renders the message wrapped
+// around the %+v rendering of the wrapped error, so that annotations such as
+// those of [WithCaller] are rendered wherever they occur in the chain; all
+// other verbs render the message.
+func (e *wrapError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, formatWrap(e.msg, fmt.Sprintf("%+v", e.err)))
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+}
+
+type wrapErrors struct {
+	cache messageCache
+	msg   string
+	errs  []error
+}
+
+func (e *wrapErrors) Unwrap() []error {
+	return e.errs
+}
+
+func (e *wrapErrors) Error() string {
+	return e.cache.get(e.render)
+}
+
+func (e *wrapErrors) render() string {
+	// The wrapped base error is always the last element of errs.
+	return formatWrap(e.msg, e.errs[len(e.errs)-1].Error())
+}
+
+// Format implements fmt.Formatter. Like that of a wrapError, the %+v verb
+// renders the message wrapped around the %+v rendering of the base error; all
+// other verbs render the message.
+func (e *wrapErrors) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, formatWrap(e.msg, fmt.Sprintf("%+v", e.errs[len(e.errs)-1])))
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+}
+
+type lazyWrapError struct {
+	base error
+	msg  func() string
+}
+
+func (e *lazyWrapError) Unwrap() error {
+	return e.base
+}
+
+func (e *lazyWrapError) Error() string {
+	msg := e.msg()
+	if len(msg) == 0 {
+		return e.base.Error()
+	}
+	return formatWrap(msg, e.base.Error())
 }
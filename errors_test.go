@@ -21,7 +21,11 @@
 package errors_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"strconv"
 	"testing"
@@ -44,6 +48,86 @@ func TestAs(t *testing.T) {
 
 	var dstC *net.DNSError
 	require.False(t, errors.As(chain, &dstC))
+
+	require.False(t, errors.As(nil, &dstC))
+	require.Panics(t, func() { errors.As(chain, nil) })
+	require.Panics(t, func() { errors.As(chain, dstC) })
+	require.Panics(t, func() { errors.As(chain, new(string)) })
+}
+
+func TestAsType(t *testing.T) {
+	type tester interface {
+		error
+		IsTest() bool
+	}
+
+	var (
+		target = testError("testError")
+		other  = errors.New("other")
+	)
+
+	cases := map[string]struct {
+		give   error
+		wantOK bool
+	}{
+		"nil":       {give: nil, wantOK: false},
+		"not found": {give: errors.Wrap(other, "oops"), wantOK: false},
+		"direct":    {give: target, wantOK: true},
+		"wrapped":   {give: errors.Wrap(errors.Wrap(target, "a"), "b"), wantOK: true},
+		"fmt":       {give: fmt.Errorf("oops: %w", target), wantOK: true},
+		"joined":    {give: errors.Join(other, errors.Wrap(target, "a")), wantOK: true},
+		"lazy": {
+			give:   errors.Lazy(func() error { return errors.Wrap(target, "a") }),
+			wantOK: true,
+		},
+		"lazy nil": {
+			give:   errors.Lazy(func() error { return nil }),
+			wantOK: false,
+		},
+		"as method": {
+			give:   asError{target: target},
+			wantOK: true,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			have, ok := errors.AsType[testError](tt.give)
+			require.Equal(t, tt.wantOK, ok)
+
+			var want testError
+			require.Equal(t, tt.wantOK, errors.As(tt.give, &want))
+			require.Equal(t, want, have)
+
+			var wantIface tester
+			iface, ok := errors.AsType[tester](tt.give)
+			require.Equal(t, errors.As(tt.give, &wantIface), ok)
+			require.Equal(t, wantIface, iface)
+		})
+	}
+}
+
+func BenchmarkAsType(b *testing.B) {
+	err := errors.Wrap(errors.Wrap(testError("testError"), "a"), "b")
+
+	b.Run("As", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var target testError
+			if !errors.As(err, &target) {
+				b.Fatal("not found")
+			}
+		}
+	})
+
+	b.Run("AsType", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, ok := errors.AsType[testError](err); !ok {
+				b.Fatal("not found")
+			}
+		}
+	})
 }
 
 func TestIs(t *testing.T) {
@@ -293,6 +377,152 @@ func TestWrapf(t *testing.T) {
 	}
 }
 
+func TestWrapf_FormatsEagerly(t *testing.T) {
+	var (
+		base  = errors.New("base")
+		names = []string{"a", "b"}
+		err   = errors.Wrapf(base, "read %v", names)
+	)
+
+	names[0] = "c"
+	require.EqualError(t, err, "read [a b]: base")
+}
+
+func TestWrapf_MultipleWrapped(t *testing.T) {
+	var (
+		errA = errors.New("a")
+		errB = errors.New("b")
+		errC = errors.New("c")
+		base = errors.New("base")
+	)
+
+	err := errors.Wrapf(base, "single %w", errA)
+	require.Equal(t, "single a: base", err.Error())
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, base)
+
+	err = errors.Wrapf(base, "multiple %w %w", errB, errC)
+	require.Equal(t, "multiple b c: base", err.Error())
+	require.ErrorIs(t, err, errB)
+	require.ErrorIs(t, err, errC)
+	require.ErrorIs(t, err, base)
+}
+
+func TestWrapEach(t *testing.T) {
+	var (
+		errA  = errors.New("a")
+		errB  = errors.New("b")
+		msgFn = func(i int, err error) string {
+			require.NotNil(t, err)
+			return fmt.Sprintf("file %d", i)
+		}
+	)
+
+	require.Nil(t, errors.WrapEach(nil, msgFn))
+	require.NoError(t, errors.WrapEachJoin(nil, msgFn))
+	require.NoError(t, errors.WrapEachJoin([]error{nil, nil}, msgFn))
+
+	wrapped := errors.WrapEach([]error{errA, nil, errB}, msgFn)
+	require.Len(t, wrapped, 3)
+	require.EqualError(t, wrapped[0], "file 0: a")
+	require.NoError(t, wrapped[1])
+	require.EqualError(t, wrapped[2], "file 2: b")
+	require.ErrorIs(t, wrapped[2], errB)
+
+	err := errors.WrapEachJoin([]error{errA, nil, errB}, msgFn)
+	require.EqualError(t, err, "file 0: a\nfile 2: b")
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+}
+
+func TestSetWrapFormatter(t *testing.T) {
+	base := errors.New("base")
+	before := errors.Wrap(base, "before")
+	require.Equal(t, "before: base", before.Error())
+
+	restore := errors.SetWrapFormatter(func(msg string, base string) string {
+		return base + " (" + msg + ")"
+	})
+	var (
+		wrapped     = errors.Wrap(base, "wrap")
+		wrappedf    = errors.Wrapf(base, "wrap%s", "f")
+		lazyWrapped = errors.LazyWrap(base, func() string { return "lazy" })
+	)
+	require.Equal(t, "base (wrap)", wrapped.Error())
+	require.Equal(t, "base (wrapf)", wrappedf.Error())
+	require.Equal(t, "base (lazy)", lazyWrapped.Error())
+	require.Equal(t, "base (before)", before.Error())
+
+	restoreDefault := errors.SetWrapFormatter(nil)
+	require.Equal(t, "default: base", errors.Wrap(base, "default").Error())
+	restoreDefault()
+	require.Equal(t, "base (custom)", errors.Wrap(base, "custom").Error())
+
+	restore()
+	require.Equal(t, "after: base", errors.Wrap(base, "after").Error())
+	require.Equal(t, "before: base", before.Error())
+	require.Equal(t, "wrap: base", wrapped.Error())
+	require.ErrorIs(t, wrapped, base)
+}
+
+var _errSink error
+
+func TestError_Cached(t *testing.T) {
+	var (
+		base = errors.New("base")
+		errs = map[string]error{
+			"wrap":        errors.Wrap(base, "wrap"),
+			"wrapf":       errors.Wrapf(base, "wrap %w", io.EOF),
+			"wrapf no %w": errors.Wrapf(base, "wrap %d", 1),
+			"join":        errors.Join(errors.Wrap(base, "a"), errors.Wrap(base, "b")),
+		}
+	)
+
+	for name, err := range errs {
+		t.Run(name, func(t *testing.T) {
+			var (
+				want = err.Error()
+				have string
+			)
+			allocs := testing.AllocsPerRun(100, func() {
+				have = err.Error()
+			})
+			require.Zero(t, allocs)
+			require.Equal(t, want, have)
+		})
+	}
+}
+
+func TestWrap_Allocs(t *testing.T) {
+	base := errors.New("base")
+	allocs := testing.AllocsPerRun(100, func() {
+		_errSink = errors.Wrap(base, "reading header")
+	})
+	require.Equal(t, float64(1), allocs)
+}
+
+func BenchmarkWrap(b *testing.B) {
+	base := errors.New("base")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_errSink = errors.Wrap(base, "reading header")
+	}
+}
+
+func BenchmarkWrapf(b *testing.B) {
+	base := errors.New("base")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_errSink = errors.Wrapf(base, "reading header %d", i)
+	}
+}
+
 func TestJoinFuncs(t *testing.T) {
 	var (
 		errA    = errors.New("a")
@@ -378,6 +608,98 @@ func TestJoinFuncs(t *testing.T) {
 	}
 }
 
+func TestFirstFunc(t *testing.T) {
+	var (
+		errA  = errors.New("a")
+		errB  = errors.New("b")
+		calls int
+		call  = func(err error) errors.ErrorFunc {
+			return func() error {
+				calls++
+				return err
+			}
+		}
+	)
+
+	cases := map[string]struct {
+		want      error
+		give      []errors.ErrorFunc
+		wantCalls int
+	}{
+		"empty": {
+			give:      nil,
+			want:      nil,
+			wantCalls: 0,
+		},
+		"no errors": {
+			give:      []errors.ErrorFunc{call(nil), nil, call(nil)},
+			want:      nil,
+			wantCalls: 2,
+		},
+		"first": {
+			give:      []errors.ErrorFunc{call(errA), call(errB)},
+			want:      errA,
+			wantCalls: 1,
+		},
+		"middle": {
+			give: []errors.ErrorFunc{
+				call(nil),
+				call(nil),
+				call(errB),
+				call(errA),
+			},
+			want:      errB,
+			wantCalls: 3,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls = 0
+			require.Equal(t, tt.want, errors.FirstFunc(tt.give...))
+			require.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestJoinFuncsContext(t *testing.T) {
+	var (
+		errA        = errors.New("a")
+		errB        = errors.New("b")
+		calls       []string
+		ctx, cancel = context.WithCancel(context.Background())
+		call        = func(name string, err error) func(context.Context) error {
+			return func(context.Context) error {
+				calls = append(calls, name)
+				return err
+			}
+		}
+	)
+	defer cancel()
+
+	require.NoError(t, errors.JoinFuncsContext(ctx))
+	require.NoError(t, errors.JoinFuncsContext(ctx, nil, call("a", nil)))
+	require.Equal(t, errA, errors.JoinFuncsContext(ctx, call("b", errA), nil))
+
+	err := errors.JoinFuncsContext(
+		ctx,
+		call("c", errA),
+		func(context.Context) error {
+			calls = append(calls, "d")
+			cancel()
+			return errB
+		},
+		call("e", nil),
+	)
+	require.EqualError(t, err, "a\nb\ncontext canceled")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []string{"a", "b", "c", "d"}, calls)
+
+	require.Equal(t, context.Canceled, errors.JoinFuncsContext(ctx, call("f", nil)))
+	require.NoError(t, errors.JoinFuncsContext(ctx, nil))
+	require.Len(t, calls, 4)
+}
+
 func TestAppendFunc(t *testing.T) {
 	cases := map[string]struct {
 		lower     error
@@ -578,14 +900,41 @@ func TestLazy_Unwrap(t *testing.T) {
 
 	unwrapped := errors.Unwrap(err)
 	require.Error(t, unwrapped)
-	require.ErrorContains(t, unwrapped, t.Name())
-	require.NotContains(t, unwrapped.Error(), "wrapped")
+	require.Equal(t, "wrapped: "+t.Name(), unwrapped.Error())
+	require.Equal(t, testError(t.Name()), errors.Unwrap(unwrapped))
 
 	var dst testError
 	require.True(t, errors.As(err, &dst))
 	require.Equal(t, t.Name(), string(dst))
 }
 
+func TestLazy_Traversal(t *testing.T) {
+	err := errors.Wrap(errors.Lazy(func() error {
+		return errors.WithField(errors.Wrap(io.EOF, "read"), "foo", "bar")
+	}), "load")
+
+	var visited []string
+	errors.Walk(err, func(err error) bool {
+		visited = append(visited, err.Error())
+		return true
+	})
+	require.Equal(t, []string{
+		"load: read: EOF",
+		"read: EOF",
+		"read: EOF",
+		"read: EOF",
+		"EOF",
+	}, visited)
+
+	require.Equal(t, map[string]any{"foo": "bar"}, errors.Fields(err))
+	require.ErrorIs(t, err, io.EOF)
+
+	err = errors.Lazy(func() error {
+		return errors.WithHint(io.EOF, "retry")
+	})
+	require.Equal(t, []string{"retry"}, errors.Hints(err))
+}
+
 func TestLazy_Error(t *testing.T) {
 	err := errors.Lazy(func() error {
 		return testError(t.Name())
@@ -593,6 +942,176 @@ func TestLazy_Error(t *testing.T) {
 	require.Equal(t, t.Name(), err.Error())
 }
 
+func TestLazy_Nil(t *testing.T) {
+	err := errors.Lazy(func() error {
+		return nil
+	})
+
+	require.Error(t, err)
+	require.Equal(t, "", err.Error())
+	require.False(t, errors.Is(err, nil))
+	require.False(t, errors.Is(err, io.EOF))
+	require.Nil(t, errors.Unwrap(err))
+
+	var dst testError
+	require.False(t, errors.As(err, &dst))
+
+	nested := errors.Lazy(func() error {
+		return err
+	})
+	require.NoError(t, errors.LazyOrNil(err))
+	require.NoError(t, errors.LazyOrNil(nested))
+	require.NoError(t, errors.LazyOrNil(nil))
+
+	notNil := errors.New("foo")
+	require.Equal(t, notNil, errors.LazyOrNil(notNil))
+}
+
+func TestLazy_NilCombined(t *testing.T) {
+	var (
+		errA    = errors.New("a")
+		calls   int
+		lazyNil = errors.Lazy(func() error {
+			calls++
+			return nil
+		})
+		nilFunc = func() error { return lazyNil }
+	)
+
+	joined := errors.Join(lazyNil, nil, lazyNil)
+	require.Error(t, joined)
+	require.Error(t, errors.JoinFuncs(nilFunc, nilFunc))
+	require.Error(t, errors.AppendFunc(lazyNil, nilFunc))
+	require.Error(t, errors.AppendFuncs(lazyNil, nilFunc, nilFunc))
+	require.Error(t, errors.FirstFunc(nilFunc))
+	require.Zero(t, calls, "collecting lazy errors must not evaluate them")
+
+	require.Empty(t, joined.Error())
+	require.Equal(t, 1, calls)
+	require.Nil(t, errors.Describe(lazyNil))
+	require.Empty(t, errors.Describe(joined).Causes)
+	require.Equal(t, errors.CodeOK, errors.CodeOf(lazyNil))
+	require.Equal(t, errA.Error(), errors.Join(lazyNil, errA).Error())
+	require.Equal(t, errA.Error(), errors.Join(errA, lazyNil).Error())
+	require.Equal(t, errA.Error(), errors.AppendFunc(errA, nilFunc).Error())
+}
+
+func TestLazy_Format(t *testing.T) {
+	err := errors.Lazy(func() error {
+		return formatError("plain")
+	})
+	require.Equal(t, "plain", fmt.Sprintf("%s", err))
+	require.Equal(t, "plain", fmt.Sprintf("%v", err))
+	require.Equal(t, "verbose plain", fmt.Sprintf("%+v", err))
+
+	err = errors.Lazy(func() error {
+		return testError("quoted")
+	})
+	require.Equal(t, `"quoted"`, fmt.Sprintf("%q", err))
+
+	err = errors.Lazy(func() error { return nil })
+	require.Equal(t, `""`, fmt.Sprintf("%q", err))
+	require.Equal(t, "", fmt.Sprintf("%v", err))
+}
+
+func TestLazy_MarshalJSON(t *testing.T) {
+	err := errors.Lazy(func() error {
+		return jsonError("foo")
+	})
+
+	raw, jsonErr := json.Marshal(err)
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{"message":"foo"}`, string(raw))
+
+	err = errors.Lazy(func() error { return nil })
+	raw, jsonErr = json.Marshal(err)
+	require.NoError(t, jsonErr)
+	require.Equal(t, "null", string(raw))
+}
+
+func TestLazy_LogValue(t *testing.T) {
+	err := errors.Lazy(func() error {
+		return jsonError("foo")
+	})
+
+	valuer, ok := err.(slog.LogValuer)
+	require.True(t, ok)
+	require.Equal(t, "foo", valuer.LogValue().String())
+
+	err = errors.Lazy(func() error {
+		return testError("bar")
+	})
+	valuer, ok = err.(slog.LogValuer)
+	require.True(t, ok)
+	require.Equal(t, testError("bar"), valuer.LogValue().Any())
+}
+
+func TestLazyResult(t *testing.T) {
+	var (
+		calls   int
+		lazyErr = errors.New("lazy")
+		err     = errors.Lazy(func() error {
+			calls++
+			return lazyErr
+		})
+		wrapped = errors.LazyWrap(err, func() string { return "wrapped" })
+	)
+
+	require.False(t, errors.LazyEvaluated(err))
+	require.False(t, errors.LazyEvaluated(wrapped))
+
+	haveErr, ok := errors.LazyResult(wrapped)
+	require.False(t, ok)
+	require.NoError(t, haveErr)
+	require.Zero(t, calls)
+
+	require.Equal(t, "lazy", err.Error())
+	require.True(t, errors.LazyEvaluated(err))
+	require.True(t, errors.LazyEvaluated(wrapped))
+
+	haveErr, ok = errors.LazyResult(wrapped)
+	require.True(t, ok)
+	require.Equal(t, lazyErr, haveErr)
+	require.Equal(t, 1, calls)
+}
+
+func TestLazyResult_NotLazy(t *testing.T) {
+	require.False(t, errors.LazyEvaluated(nil))
+	require.False(t, errors.LazyEvaluated(errors.New("foo")))
+}
+
+func TestLazyWrap(t *testing.T) {
+	var (
+		calls   int
+		baseErr = errors.New("base")
+		err     = errors.LazyWrap(baseErr, func() string {
+			calls++
+			return "lazy"
+		})
+	)
+
+	require.ErrorIs(t, err, baseErr)
+	require.Equal(t, baseErr, errors.Unwrap(err))
+	require.Zero(t, calls)
+
+	require.Equal(t, "lazy: base", err.Error())
+	require.Equal(t, "lazy: base", err.Error())
+	require.Equal(t, 1, calls)
+}
+
+func TestLazyWrap_Nil(t *testing.T) {
+	require.NoError(t, errors.LazyWrap(nil, func() string {
+		require.FailNow(t, "message should not be evaluated")
+		return ""
+	}))
+
+	baseErr := errors.New("base")
+	require.Equal(t, baseErr, errors.LazyWrap(baseErr, nil))
+
+	err := errors.LazyWrap(baseErr, func() string { return "" })
+	require.Equal(t, baseErr.Error(), err.Error())
+}
+
 func newChain(size int) []error {
 	var (
 		errs []error
@@ -621,3 +1140,49 @@ func (e testError) Error() string {
 func (e testError) IsTest() bool {
 	return true
 }
+
+type formatError string
+
+func (e formatError) Error() string {
+	return string(e)
+}
+
+func (e formatError) Format(s fmt.State, verb rune) {
+	if s.Flag('+') {
+		fmt.Fprintf(s, "verbose %s", string(e))
+		return
+	}
+	fmt.Fprint(s, string(e))
+}
+
+type jsonError string
+
+func (e jsonError) Error() string {
+	return string(e)
+}
+
+func (e jsonError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"message": string(e)})
+}
+
+func (e jsonError) LogValue() slog.Value {
+	return slog.StringValue(string(e))
+}
+
+type asError struct {
+	target testError
+}
+
+func (e asError) Error() string {
+	return "as"
+}
+
+func (e asError) As(target any) bool {
+	switch x := target.(type) {
+	case *testError:
+		*x = e.target
+		return true
+	default:
+		return false
+	}
+}
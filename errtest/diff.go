@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mway.dev/errors"
+)
+
+// Diff compares the structures of want and got, as described by
+// errors.Describe, and returns a readable report of their differences, one
+// per line. Each line is prefixed by the path of the differing node, where
+// "error" is the outermost node and "error.causes[i]" is its i-th cause.
+// Types, messages, codes, fields, and the shape of joined errors are
+// compared; stacks are ignored. If the structures are equal, Diff returns an
+// empty string.
+func Diff(want error, got error) string {
+	var d differ
+	d.node("error", errors.Describe(want), errors.Describe(got))
+	return strings.Join(d.lines, "\n")
+}
+
+type differ struct {
+	lines []string
+}
+
+func (d *differ) addf(path string, format string, args ...any) {
+	d.lines = append(d.lines, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (d *differ) node(path string, want *errors.Node, got *errors.Node) {
+	switch {
+	case want == nil && got == nil:
+		return
+	case want == nil:
+		d.addf(path, "want nil, got %q", got.Message)
+		return
+	case got == nil:
+		d.addf(path, "want %q, got nil", want.Message)
+		return
+	}
+
+	d.value(path+".type", want.Type, got.Type)
+	d.value(path+".message", want.Message, got.Message)
+	d.value(path+".code", want.Code, got.Code)
+	d.fields(path+".fields", want.Fields, got.Fields)
+
+	if len(want.Causes) != len(got.Causes) {
+		d.addf(
+			path+".causes",
+			"want %d cause(s), got %d",
+			len(want.Causes),
+			len(got.Causes),
+		)
+	}
+
+	for i := 0; i < len(want.Causes) && i < len(got.Causes); i++ {
+		d.node(
+			fmt.Sprintf("%s.causes[%d]", path, i),
+			want.Causes[i],
+			got.Causes[i],
+		)
+	}
+}
+
+func (d *differ) value(path string, want string, got string) {
+	if want != got {
+		d.addf(path, "want %q, got %q", want, got)
+	}
+}
+
+func (d *differ) fields(path string, want map[string]any, got map[string]any) {
+	keys := make([]string, 0, len(want)+len(got))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		wantValue, wantOK := want[k]
+		gotValue, gotOK := got[k]
+
+		switch {
+		case !gotOK:
+			d.addf(path+"."+k, "want %#v, got none", wantValue)
+		case !wantOK:
+			d.addf(path+"."+k, "want none, got %#v", gotValue)
+		case !reflect.DeepEqual(wantValue, gotValue):
+			d.addf(path+"."+k, "want %#v, got %#v", wantValue, gotValue)
+		}
+	}
+}
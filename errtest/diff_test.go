@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/errtest"
+)
+
+func TestDiff(t *testing.T) {
+	base := errors.WithField(errors.NewCoded(errors.CodeNotFound, "not found"), "id", 1)
+
+	cases := map[string]struct {
+		giveWant error
+		giveGot  error
+		want     string
+	}{
+		"equal": {
+			giveWant: errors.Join(errors.Wrap(base, "get"), errors.New("foo")),
+			giveGot:  errors.Join(errors.Wrap(base, "get"), errors.New("foo")),
+			want:     "",
+		},
+		"nil": {
+			giveWant: nil,
+			giveGot:  nil,
+			want:     "",
+		},
+		"want nil": {
+			giveWant: nil,
+			giveGot:  errors.New("foo"),
+			want:     `error: want nil, got "foo"`,
+		},
+		"got nil": {
+			giveWant: errors.New("foo"),
+			giveGot:  nil,
+			want:     `error: want "foo", got nil`,
+		},
+		"message": {
+			giveWant: errors.New("foo"),
+			giveGot:  errors.New("bar"),
+			want:     `error.message: want "foo", got "bar"`,
+		},
+		"code": {
+			giveWant: errors.NewCoded(errors.CodeNotFound, "foo"),
+			giveGot:  errors.NewCoded(errors.CodeInternal, "foo"),
+			want:     `error.code: want "not_found", got "internal"`,
+		},
+		"fields": {
+			giveWant: errors.WithFields(errors.New("foo"), map[string]any{"a": 1, "b": 2}),
+			giveGot:  errors.WithFields(errors.New("foo"), map[string]any{"b": 3, "c": 4}),
+			want: "error.fields.a: want 1, got none\n" +
+				"error.fields.b: want 2, got 3\n" +
+				"error.fields.c: want none, got 4",
+		},
+		"join shape": {
+			giveWant: errors.Join(errors.New("foo"), errors.New("bar")),
+			giveGot:  errors.Join(errors.New("foo"), errors.New("baz"), errors.New("qux")),
+			want: `error.message: want "foo\nbar", got "foo\nbaz\nqux"` + "\n" +
+				"error.causes: want 2 cause(s), got 3\n" +
+				`error.causes[1].message: want "bar", got "baz"`,
+		},
+		"nested": {
+			giveWant: errors.Wrap(base, "get"),
+			giveGot:  errors.Wrap(errors.New("not found"), "get"),
+			want: `error.causes[0].type: want "*errors.codedError", ` +
+				`got "*errors.errorString"` + "\n" +
+				`error.causes[0].code: want "not_found", got ""` + "\n" +
+				"error.causes[0].fields.id: want 1, got none",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errtest.Diff(tt.giveWant, tt.giveGot))
+		})
+	}
+}
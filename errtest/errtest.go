@@ -0,0 +1,196 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package errtest provides helpers for testing errors.
+package errtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mway.dev/errors"
+)
+
+// AssertChain asserts that err's chain contains each of the given items, in
+// order from outermost to innermost. Each item may be either:
+//
+//   - a string, which matches an error in the chain whose message equals or
+//     begins with the string; or
+//   - an error, which matches the error in the chain at which the target
+//     first becomes reachable according to errors.Is.
+//
+// The chain is traversed using errors.Unwrap; joined errors terminate the
+// traversal. For example:
+//
+//	errtest.AssertChain(t, err, "load config", "open file", fs.ErrNotExist)
+//
+// AssertChain reports whether the assertion succeeded.
+func AssertChain(t testing.TB, err error, items ...any) bool {
+	t.Helper()
+
+	chain := unwrapChain(err)
+	cursor := 0
+	for n, item := range items {
+		idx := -1
+		for i := cursor; i < len(chain) && idx < 0; i++ {
+			if chainItemMatches(chain, i, item) {
+				idx = i
+			}
+		}
+
+		if idx < 0 {
+			t.Errorf(
+				"error chain does not contain item %d (%s) after position %d\nchain:\n%s",
+				n,
+				describeItem(item),
+				cursor,
+				describeChain(chain),
+			)
+			return false
+		}
+		cursor = idx + 1
+	}
+
+	return true
+}
+
+// AssertCode asserts that err's code, as returned by errors.CodeOf, is equal
+// to want. AssertCode reports whether the assertion succeeded.
+func AssertCode(t testing.TB, err error, want errors.Code) bool {
+	t.Helper()
+
+	if have := errors.CodeOf(err); have != want {
+		t.Errorf("error code mismatch: want %s, have %s (error: %v)", want, have, err)
+		return false
+	}
+	return true
+}
+
+// AssertFields asserts that err's fields, as returned by errors.Fields,
+// contain each of the given key-value pairs. Fields not present in want are
+// ignored. AssertFields reports whether the assertion succeeded.
+func AssertFields(t testing.TB, err error, want map[string]any) bool {
+	t.Helper()
+
+	have := errors.Fields(err)
+	ok := true
+	for k, wantValue := range want {
+		haveValue, exists := have[k]
+		switch {
+		case !exists:
+			t.Errorf("error field %q missing (fields: %v)", k, have)
+			ok = false
+		case !reflect.DeepEqual(wantValue, haveValue):
+			t.Errorf(
+				"error field %q mismatch: want %#v, have %#v",
+				k,
+				wantValue,
+				haveValue,
+			)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// AssertJoinedLen asserts that err joins exactly want errors. The first
+// joined error in err's chain (i.e. the first error with an Unwrap() []error
+// method) is inspected; if err's chain contains no joined errors, err is
+// considered to join one error, or zero if err is nil. AssertJoinedLen
+// reports whether the assertion succeeded.
+func AssertJoinedLen(t testing.TB, err error, want int) bool {
+	t.Helper()
+
+	if have := joinedLen(err); have != want {
+		t.Errorf("joined error count mismatch: want %d, have %d (error: %v)", want, have, err)
+		return false
+	}
+	return true
+}
+
+// unwrapChain returns the errors in err's chain, up to and including the
+// first joined error.
+func unwrapChain(err error) []error {
+	var chain []error
+	errors.Walk(err, func(err error) bool {
+		chain = append(chain, err)
+		_, joined := err.(interface{ Unwrap() []error })
+		return !joined
+	})
+	return chain
+}
+
+func chainItemMatches(chain []error, i int, item any) bool {
+	switch x := item.(type) {
+	case string:
+		return strings.HasPrefix(chain[i].Error(), x)
+	case error:
+		if !errors.Is(chain[i], x) {
+			return false
+		}
+		return i == len(chain)-1 || !errors.Is(chain[i+1], x)
+	default:
+		return false
+	}
+}
+
+func joinedLen(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	n := 1
+	errors.Walk(err, func(err error) bool {
+		joined, ok := err.(interface{ Unwrap() []error })
+		if ok {
+			n = len(joined.Unwrap())
+		}
+		return !ok
+	})
+	return n
+}
+
+func describeItem(item any) string {
+	switch x := item.(type) {
+	case string:
+		return fmt.Sprintf("message %q", x)
+	case error:
+		return fmt.Sprintf("error %T(%q)", x, x.Error())
+	default:
+		return fmt.Sprintf("unsupported item %T", x)
+	}
+}
+
+func describeChain(chain []error) string {
+	if len(chain) == 0 {
+		return "  <nil>"
+	}
+
+	var b strings.Builder
+	for i, err := range chain {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "  %d: %T: %q", i, err, err.Error())
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest_test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/errtest"
+)
+
+func TestAssertChain(t *testing.T) {
+	err := errors.Wrap(
+		errors.Wrap(&fs.PathError{Op: "open", Path: "/app.yaml", Err: fs.ErrNotExist}, "open file"),
+		"load config",
+	)
+
+	require.True(t, errtest.AssertChain(t, err, "load config", "open file", fs.ErrNotExist))
+	require.True(t, errtest.AssertChain(t, err, "open file"))
+	require.True(t, errtest.AssertChain(t, err, fs.ErrNotExist))
+	require.True(t, errtest.AssertChain(t, err))
+
+	cases := map[string][]any{
+		"out of order":    {"open file", "load config"},
+		"missing message": {"load config", "parse file"},
+		"missing error":   {"load config", io.EOF},
+		"unsupported":     {42},
+	}
+
+	for name, items := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			require.False(t, errtest.AssertChain(rec, err, items...))
+			require.Len(t, rec.errors, 1)
+			require.Contains(t, rec.errors[0], "error chain does not contain")
+		})
+	}
+
+	rec := &recorder{TB: t}
+	require.False(t, errtest.AssertChain(rec, nil, "foo"))
+	require.Contains(t, rec.errors[0], "<nil>")
+}
+
+func TestAssertCode(t *testing.T) {
+	err := errors.Wrap(errors.NewCoded(errors.CodeNotFound, "not found"), "get")
+	require.True(t, errtest.AssertCode(t, err, errors.CodeNotFound))
+
+	rec := &recorder{TB: t}
+	require.False(t, errtest.AssertCode(rec, err, errors.CodeInternal))
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "want internal, have not_found")
+}
+
+func TestAssertFields(t *testing.T) {
+	err := errors.WithFields(errors.New("foo"), map[string]any{
+		"user_id": 42,
+		"tags":    []string{"a", "b"},
+	})
+
+	require.True(t, errtest.AssertFields(t, err, map[string]any{"user_id": 42}))
+	require.True(t, errtest.AssertFields(t, err, map[string]any{"tags": []string{"a", "b"}}))
+
+	rec := &recorder{TB: t}
+	require.False(t, errtest.AssertFields(rec, err, map[string]any{
+		"user_id": 43,
+		"missing": true,
+	}))
+	require.Len(t, rec.errors, 2)
+}
+
+func TestAssertJoinedLen(t *testing.T) {
+	var (
+		errA = errors.New("a")
+		errB = errors.New("b")
+	)
+
+	require.True(t, errtest.AssertJoinedLen(t, errors.Join(errA, errB), 2))
+	require.True(t, errtest.AssertJoinedLen(t, errors.Wrap(errors.Join(errA, errB), "x"), 2))
+	require.True(t, errtest.AssertJoinedLen(t, errA, 1))
+	require.True(t, errtest.AssertJoinedLen(t, nil, 0))
+
+	rec := &recorder{TB: t}
+	require.False(t, errtest.AssertJoinedLen(rec, errors.Join(errA, errB), 3))
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "want 3, have 2")
+}
+
+func TestCycles(t *testing.T) {
+	self := &cyclicJoinError{}
+	self.errs = []error{self, self, fs.ErrNotExist}
+	err := errors.Wrap(self, "load config")
+
+	require.True(t, errtest.AssertChain(t, err, "load config", "cyclic join"))
+	require.True(t, errtest.AssertJoinedLen(t, err, 3))
+}
+
+// cyclicJoinError is a joined error that may contain itself.
+type cyclicJoinError struct {
+	errs []error
+}
+
+func (e *cyclicJoinError) Error() string {
+	return "cyclic join"
+}
+
+func (e *cyclicJoinError) Unwrap() []error {
+	return e.errs
+}
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mway.dev/errors"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to "1", causes
+// AssertGolden to write golden files instead of comparing against them.
+const UpdateGoldenEnv = "ERRTEST_UPDATE_GOLDEN"
+
+// CanonicalJSON returns a canonical, indented JSON encoding of err's
+// structure, as produced by errors.Describe. Object keys are emitted in a
+// stable order, stacks and callers are normalized with NormalizeStack, and
+// times and durations are replaced with their zero values. If err is nil,
+// CanonicalJSON encodes a null value.
+func CanonicalJSON(err error) ([]byte, error) {
+	node := errors.Describe(err)
+	normalizeNode(node)
+
+	raw, jsonErr := json.MarshalIndent(node, "", "  ")
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+	return append(raw, '\n'), nil
+}
+
+// AssertGolden asserts that the canonical JSON encoding of err, as returned by
+// CanonicalJSON, is equal to the contents of the golden file at path. If they
+// differ, the failure message includes a unified diff.
+//
+// If the UpdateGoldenEnv environment variable is set to "1", AssertGolden
+// instead writes the encoding to path, creating any missing directories.
+// AssertGolden reports whether the assertion succeeded.
+func AssertGolden(t testing.TB, err error, path string) bool {
+	t.Helper()
+
+	have, jsonErr := CanonicalJSON(err)
+	if jsonErr != nil {
+		t.Errorf("failed to encode error: %v", jsonErr)
+		return false
+	}
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o755); mkdirErr != nil {
+			t.Errorf("failed to create golden file directory: %v", mkdirErr)
+			return false
+		}
+		if writeErr := os.WriteFile(path, have, 0o644); writeErr != nil {
+			t.Errorf("failed to write golden file: %v", writeErr)
+			return false
+		}
+		return true
+	}
+
+	want, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Errorf(
+			"failed to read golden file (set %s=1 to create it): %v",
+			UpdateGoldenEnv,
+			readErr,
+		)
+		return false
+	}
+
+	if !bytes.Equal(want, have) {
+		t.Errorf(
+			"error does not match golden file %s (set %s=1 to update it):\n%s",
+			path,
+			UpdateGoldenEnv,
+			unifiedDiff(path, string(want), "actual", string(have)),
+		)
+		return false
+	}
+
+	return true
+}
+
+// diffContext is the number of unchanged lines shown around each change in
+// the diffs reported by AssertGolden.
+const diffContext = 3
+
+type diffLine struct {
+	text string
+	op   byte
+}
+
+// unifiedDiff returns a line-based diff of want and have in the style of a
+// unified diff, without line numbers in hunk headers.
+func unifiedDiff(wantName string, want string, haveName string, have string) string {
+	lines := diffLines(
+		strings.Split(strings.TrimSuffix(want, "\n"), "\n"),
+		strings.Split(strings.TrimSuffix(have, "\n"), "\n"),
+	)
+
+	var b strings.Builder
+	b.WriteString("--- " + wantName + "\n")
+	b.WriteString("+++ " + haveName + "\n")
+
+	last := -1
+	for i, line := range lines {
+		if !nearChange(lines, i) {
+			continue
+		}
+		if i != last+1 {
+			b.WriteString("@@\n")
+		}
+		b.WriteByte(line.op)
+		b.WriteString(line.text)
+		b.WriteByte('\n')
+		last = i
+	}
+	return b.String()
+}
+
+// diffLines returns the edit script that turns want into have, based on their
+// longest common subsequence of lines.
+func diffLines(want []string, have []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of want[i:]
+	// and have[j:].
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(have)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(have) - 1; j >= 0; j-- {
+			if want[i] == have[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, max(len(want), len(have)))
+	i, j := 0, 0
+	for i < len(want) || j < len(have) {
+		switch {
+		case i < len(want) && j < len(have) && want[i] == have[j]:
+			lines = append(lines, diffLine{op: ' ', text: want[i]})
+			i++
+			j++
+		case j == len(have) || (i < len(want) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{op: '-', text: want[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{op: '+', text: have[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+func nearChange(lines []diffLine, i int) bool {
+	for j := max(0, i-diffContext); j <= min(len(lines)-1, i+diffContext); j++ {
+		if lines[j].op != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeNode(node *errors.Node) {
+	if node == nil {
+		return
+	}
+	if len(node.Stack) > 0 {
+		node.Stack = NormalizeStack(node.Stack)
+	}
+	if len(node.Caller) > 0 {
+		node.Caller = strings.TrimPrefix(NormalizeStack("\t"+node.Caller), "\t")
+	}
+	if len(node.Time) > 0 {
+		node.Time = time.Time{}.Format(time.RFC3339Nano)
+	}
+	if len(node.Duration) > 0 {
+		node.Duration = time.Duration(0).String()
+	}
+	for _, cause := range node.Causes {
+		normalizeNode(cause)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/errtest"
+)
+
+func newGoldenError() error {
+	return errors.Join(
+		errors.Wrap(
+			errors.WithFields(errors.NewCoded(errors.CodeNotFound, "not found"), map[string]any{
+				"user_id": 42,
+				"region":  "us-east-1",
+			}),
+			"get user",
+		),
+		errors.New("cache unavailable"),
+	)
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	raw, err := errtest.CanonicalJSON(errors.Recover("boom"))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"stack": "goroutine N [running]:`)
+	require.NotRegexp(t, `\.go:\d+`, string(raw))
+
+	raw, err = errtest.CanonicalJSON(errors.WithCaller(errors.New("foo")))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"caller": "go.mway.dev/errors/errtest/golden_test.go:N"`)
+
+	raw, err = errtest.CanonicalJSON(errors.WithTime(errors.New("foo")))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"time": "0001-01-01T00:00:00Z"`)
+
+	raw, err = errtest.CanonicalJSON(errors.WithDuration(errors.New("foo"), time.Second))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"duration": "0s"`)
+
+	raw, err = errtest.CanonicalJSON(nil)
+	require.NoError(t, err)
+	require.Equal(t, "null\n", string(raw))
+}
+
+func TestAssertGolden(t *testing.T) {
+	require.True(t, errtest.AssertGolden(t, newGoldenError(), "testdata/golden.json"))
+}
+
+func TestAssertGolden_Time(t *testing.T) {
+	err := errors.WithTime(errors.New("timed out"))
+	require.True(t, errtest.AssertGolden(t, err, "testdata/golden_time.json"))
+}
+
+func TestAssertGolden_Duration(t *testing.T) {
+	err := errors.Timed(func() error { return errors.New("timed out") })
+	require.True(t, errtest.AssertGolden(t, err, "testdata/golden_duration.json"))
+}
+
+func TestAssertGolden_Mismatch(t *testing.T) {
+	rec := &recorder{TB: t}
+	err := errors.Wrap(errors.New("cache unavailable"), "get user")
+	require.False(t, errtest.AssertGolden(rec, err, "testdata/golden.json"))
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "does not match golden file testdata/golden.json")
+	require.Contains(t, rec.errors[0], "--- testdata/golden.json")
+	require.Contains(t, rec.errors[0], "+++ actual")
+	require.Contains(t, rec.errors[0], `-  "message": "get user: not found\ncache unavailable",`)
+}
+
+func TestAssertGolden_Diff(t *testing.T) {
+	rec := &recorder{TB: t}
+	err := errors.Join(
+		errors.Wrap(
+			errors.WithFields(errors.NewCoded(errors.CodeNotFound, "not found"), map[string]any{
+				"user_id": 42,
+				"region":  "us-east-1",
+			}),
+			"get user",
+		),
+		errors.New("cache down"),
+	)
+	require.False(t, errtest.AssertGolden(rec, err, "testdata/golden.json"))
+	require.Len(t, rec.errors, 1)
+	require.Equal(t, `error does not match golden file testdata/golden.json `+
+		`(set ERRTEST_UPDATE_GOLDEN=1 to update it):
+--- testdata/golden.json
++++ actual
+ {
+-  "message": "get user: not found\ncache unavailable",
++  "message": "get user: not found\ncache down",
+   "type": "*errors.joinError",
+   "causes": [
+     {
+@@
+       ]
+     },
+     {
+-      "message": "cache unavailable",
++      "message": "cache down",
+       "type": "*errors.errorString"
+     }
+   ]
+`, rec.errors[0])
+}
+
+func TestAssertGolden_Missing(t *testing.T) {
+	rec := &recorder{TB: t}
+	path := filepath.Join(t.TempDir(), "missing.json")
+	require.False(t, errtest.AssertGolden(rec, errors.New("foo"), path))
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "failed to read golden file")
+}
+
+func TestAssertGolden_Update(t *testing.T) {
+	t.Setenv(errtest.UpdateGoldenEnv, "1")
+
+	path := filepath.Join(t.TempDir(), "nested", "golden.json")
+	require.True(t, errtest.AssertGolden(t, newGoldenError(), path))
+
+	have, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	want, err := os.ReadFile("testdata/golden.json")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(have))
+}
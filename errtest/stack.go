@@ -0,0 +1,135 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	_goroutineRegexp = regexp.MustCompile(`goroutine \d+`)
+	_frameFileRegexp = regexp.MustCompile(`^\t(.+):\d+(?: \+0x[0-9a-f]+)?$`)
+	_frameFuncRegexp = regexp.MustCompile(`^([^\t].*)\(([^()]*)\)$`)
+	_module          = sync.OnceValues(findModule)
+)
+
+// LinePlaceholder replaces line numbers in stacks normalized by
+// NormalizeStack.
+const LinePlaceholder = "N"
+
+// Format returns the %+v rendering of err, with any stacks normalized by
+// NormalizeStack. Format is intended to be used for golden-file tests of
+// verbose error output. If err is nil, Format returns an empty string.
+func Format(err error) string {
+	if err == nil {
+		return ""
+	}
+	return NormalizeStack(fmt.Sprintf("%+v", err))
+}
+
+// NormalizeStack returns a deterministic rendering of stack traces in s, as
+// formatted by runtime/debug.Stack, such that it is stable across machines
+// and refactors:
+//
+//   - file paths are trimmed of their GOROOT, GOPATH, module cache, and
+//     module directory prefixes (files within the current module are
+//     prefixed with its module path instead);
+//   - line numbers and program counter offsets are replaced by
+//     LinePlaceholder;
+//   - function arguments are replaced by "...";
+//   - goroutine IDs are replaced by "N".
+//
+// Lines that are not part of a stack trace are unmodified.
+func NormalizeStack(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case _frameFileRegexp.MatchString(line):
+			path := _frameFileRegexp.FindStringSubmatch(line)[1]
+			lines[i] = "\t" + trimPath(path) + ":" + LinePlaceholder
+		case strings.HasPrefix(line, "goroutine ") || strings.HasPrefix(line, "created by "):
+			lines[i] = _goroutineRegexp.ReplaceAllString(line, "goroutine N")
+		case _frameFuncRegexp.MatchString(line):
+			match := _frameFuncRegexp.FindStringSubmatch(line)
+			if len(match[2]) > 0 {
+				lines[i] = match[1] + "(...)"
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func trimPath(path string) string {
+	path = filepath.ToSlash(path)
+
+	if dir, modpath := _module(); len(dir) > 0 {
+		if rel, ok := strings.CutPrefix(path, dir+"/"); ok {
+			return modpath + "/" + rel
+		}
+	}
+	if idx := strings.LastIndex(path, "/pkg/mod/"); idx >= 0 {
+		return path[idx+len("/pkg/mod/"):]
+	}
+	if idx := strings.LastIndex(path, "/src/"); idx >= 0 {
+		return path[idx+len("/src/"):]
+	}
+	return filepath.Base(path)
+}
+
+// findModule returns the directory and path of the module that contains the
+// current working directory, if any.
+func findModule() (dir string, modpath string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", ""
+	}
+
+	for dir = wd; ; dir = filepath.Dir(dir) {
+		if modpath, ok := readModulePath(filepath.Join(dir, "go.mod")); ok {
+			return filepath.ToSlash(dir), modpath
+		}
+		if filepath.Dir(dir) == dir {
+			return "", ""
+		}
+	}
+}
+
+func readModulePath(gomod string) (string, bool) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if modpath, ok := strings.CutPrefix(scanner.Text(), "module "); ok {
+			return strings.Trim(strings.TrimSpace(modpath), `"`), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errtest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/errtest"
+)
+
+func TestNormalizeStack(t *testing.T) {
+	stack := strings.Join([]string{
+		"panic: boom",
+		"goroutine 42 [running]:",
+		"runtime/debug.Stack()",
+		"\t/usr/local/go/src/runtime/debug/stack.go:26 +0x5e",
+		"github.com/foo/bar.(*Baz).Do(0xc000012345, {0x1234, 0x5})",
+		"\t/home/user/go/pkg/mod/github.com/foo/bar@v1.2.3/baz.go:17 +0x1f",
+		"main.main()",
+		"\t/opt/elsewhere/main.go:9",
+		"created by main.start in goroutine 1",
+	}, "\n")
+
+	require.Equal(t, strings.Join([]string{
+		"panic: boom",
+		"goroutine N [running]:",
+		"runtime/debug.Stack()",
+		"\truntime/debug/stack.go:N",
+		"github.com/foo/bar.(*Baz).Do(...)",
+		"\tgithub.com/foo/bar@v1.2.3/baz.go:N",
+		"main.main()",
+		"\tmain.go:N",
+		"created by main.start in goroutine N",
+	}, "\n"), errtest.NormalizeStack(stack))
+}
+
+func TestFormat(t *testing.T) {
+	err := func() (err error) {
+		defer func() {
+			err = errors.Recover(recover())
+		}()
+		panic("boom")
+	}()
+
+	formatted := errtest.Format(err)
+	require.True(t, strings.HasPrefix(formatted, "panic: boom\ngoroutine N [running]:\n"))
+	require.Contains(t, formatted, "\tgo.mway.dev/errors/panic.go:N\n")
+	require.Contains(t, formatted, "\tgo.mway.dev/errors/errtest/stack_test.go:N\n")
+	require.NotRegexp(t, `:\d+`, formatted)
+
+	require.Equal(t, "foo", errtest.Format(errors.New("foo")))
+	require.Equal(t, "", errtest.Format(nil))
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Exit codes, as defined by sysexits(3), that are derived by [ExitCode].
+const (
+	ExitUsage       = 64 // EX_USAGE
+	ExitNoInput     = 66 // EX_NOINPUT
+	ExitUnavailable = 69 // EX_UNAVAILABLE
+	ExitSoftware    = 70 // EX_SOFTWARE
+	ExitTempFail    = 75 // EX_TEMPFAIL
+	ExitNoPerm      = 77 // EX_NOPERM
+)
+
+// WithExitCode returns a new error that wraps err and carries the given
+// process exit code, which can be retrieved with [ExitCode]. If err is nil,
+// WithExitCode returns nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{
+		err:  err,
+		code: code,
+	}
+}
+
+// ExitCode derives a process exit code from err. If err is nil, ExitCode
+// returns 0. Otherwise, ExitCode returns the first of:
+//
+//   - the exit code of the first error in err's chain that has an
+//     ExitCode() int method, such as errors created by [WithExitCode] or
+//     *exec.ExitError;
+//   - a sysexits(3) code for common sentinel errors (fs.ErrNotExist,
+//     fs.ErrPermission, and context.DeadlineExceeded);
+//   - a sysexits(3) code for errors that carry a [Code] (see [CodeOf]);
+//   - 1.
+func ExitCode(err error) int {
+	if LazyOrNil(err) == nil {
+		return 0
+	}
+
+	var exiter interface{ ExitCode() int }
+	if As(err, &exiter) {
+		return exiter.ExitCode()
+	}
+
+	if code, ok := sentinelExitCode(err); ok {
+		return code
+	}
+
+	var coder interface{ Code() Code }
+	if As(err, &coder) {
+		if code, ok := codeExitCode(coder.Code()); ok {
+			return code
+		}
+	}
+
+	return 1
+}
+
+// Exit prints err to os.Stderr, if err is non-nil, and exits the current
+// process with the exit code derived from err by [ExitCode]. Exit is intended
+// to be called at the end of a command-line program's main function:
+//
+//	func main() {
+//		errors.Exit(run())
+//	}
+func Exit(err error) {
+	if LazyOrNil(err) != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+	}
+	os.Exit(ExitCode(err))
+}
+
+func sentinelExitCode(err error) (int, bool) {
+	switch {
+	case Is(err, fs.ErrNotExist):
+		return ExitNoInput, true
+	case Is(err, fs.ErrPermission):
+		return ExitNoPerm, true
+	case Is(err, context.DeadlineExceeded):
+		return ExitTempFail, true
+	default:
+		return 0, false
+	}
+}
+
+func codeExitCode(code Code) (int, bool) {
+	switch code {
+	case CodeInvalidArgument, CodeOutOfRange:
+		return ExitUsage, true
+	case CodeNotFound:
+		return ExitNoInput, true
+	case CodePermissionDenied, CodeUnauthenticated:
+		return ExitNoPerm, true
+	case CodeUnavailable:
+		return ExitUnavailable, true
+	case CodeDeadlineExceeded, CodeResourceExhausted, CodeAborted:
+		return ExitTempFail, true
+	case CodeInternal, CodeDataLoss, CodeUnimplemented:
+		return ExitSoftware, true
+	default:
+		return 0, false
+	}
+}
+
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) ExitCode() int {
+	return e.code
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithExitCode(t *testing.T) {
+	var (
+		base = errors.New("foo")
+		err  = errors.WithExitCode(base, 42)
+	)
+
+	require.Equal(t, base.Error(), err.Error())
+	require.ErrorIs(t, err, base)
+	require.Equal(t, 42, errors.ExitCode(err))
+	require.Equal(t, 42, errors.ExitCode(errors.Wrap(err, "wrapped")))
+	require.NoError(t, errors.WithExitCode(nil, 42))
+}
+
+func TestExitCode(t *testing.T) {
+	cases := map[string]struct {
+		give error
+		want int
+	}{
+		"nil": {
+			give: nil,
+			want: 0,
+		},
+		"generic": {
+			give: errors.New("foo"),
+			want: 1,
+		},
+		"not exist": {
+			give: &fs.PathError{Op: "open", Path: "/foo", Err: fs.ErrNotExist},
+			want: errors.ExitNoInput,
+		},
+		"permission": {
+			give: errors.Wrap(fs.ErrPermission, "wrapped"),
+			want: errors.ExitNoPerm,
+		},
+		"deadline": {
+			give: errors.Wrap(context.DeadlineExceeded, "wrapped"),
+			want: errors.ExitTempFail,
+		},
+		"code": {
+			give: errors.NewCoded(errors.CodeInvalidArgument, "bad flag"),
+			want: errors.ExitUsage,
+		},
+		"uncategorized code": {
+			give: errors.NewCoded(errors.CodeCanceled, "canceled"),
+			want: 1,
+		},
+		"explicit overrides sentinel": {
+			give: errors.WithExitCode(fs.ErrNotExist, 3),
+			want: 3,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.ExitCode(tt.give))
+		})
+	}
+}
+
+func TestExit(t *testing.T) {
+	if os.Getenv("ERRORS_TEST_EXIT") == "1" {
+		errors.Exit(errors.WithExitCode(errors.New("something broke"), 42))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExit$")
+	cmd.Env = append(os.Environ(), "ERRORS_TEST_EXIT=1")
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(cmd.Run(), &exitErr))
+	require.Equal(t, 42, exitErr.ExitCode())
+	require.Equal(t, 42, errors.ExitCode(exitErr))
+	require.Equal(t, "error: something broke\n", stderr.String())
+}
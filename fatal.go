@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+// Fatal returns a new error that wraps err and marks it as non-recoverable,
+// e.g. so that a supervisor stops retrying the operation that failed and shuts
+// down instead. Whether an error is fatal can be checked with [IsFatal]. If
+// err is nil, Fatal returns nil; if err is already fatal, it is returned
+// verbatim.
+func Fatal(err error) error {
+	if err == nil || IsFatal(err) {
+		return err
+	}
+	return &fatalError{
+		err: err,
+	}
+}
+
+// FatalIf returns err marked as fatal (see [Fatal]) if any of matchers
+// returns true for it, and err verbatim otherwise, e.g. to classify errors
+// such as authentication failures or corrupt data as non-recoverable at the
+// boundary where they are produced. If err is nil, FatalIf returns nil.
+func FatalIf(err error, matchers ...func(error) bool) error {
+	if err == nil {
+		return err
+	}
+
+	for _, match := range matchers {
+		if match != nil && match(err) {
+			return Fatal(err)
+		}
+	}
+	return err
+}
+
+// IsFatal reports whether any error in err's chain has been marked as fatal
+// with [Fatal] or [FatalIf].
+func IsFatal(err error) bool {
+	_, ok := AsType[*fatalError](err)
+	return ok
+}
+
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Unwrap() error {
+	return e.err
+}
+
+func (e *fatalError) Error() string {
+	return e.err.Error()
+}
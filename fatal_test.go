@@ -0,0 +1,89 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestFatal(t *testing.T) {
+	require.NoError(t, errors.Fatal(nil))
+	require.False(t, errors.IsFatal(nil))
+	require.False(t, errors.IsFatal(io.EOF))
+
+	err := errors.Fatal(io.EOF)
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+	require.True(t, errors.IsFatal(err))
+	require.True(t, errors.IsFatal(fmt.Errorf("wrapped: %w", err)))
+	require.True(t, errors.IsFatal(errors.Join(io.ErrUnexpectedEOF, err)))
+	require.Same(t, err, errors.Fatal(err))
+}
+
+func TestFatalIf(t *testing.T) {
+	isEOF := func(err error) bool {
+		return errors.Is(err, io.EOF)
+	}
+
+	cases := map[string]struct {
+		give      error
+		matchers  []func(error) bool
+		wantFatal bool
+	}{
+		"nil": {
+			give:     nil,
+			matchers: []func(error) bool{isEOF},
+		},
+		"no matchers": {
+			give: io.EOF,
+		},
+		"nil matcher": {
+			give:     io.EOF,
+			matchers: []func(error) bool{nil},
+		},
+		"no match": {
+			give:     io.ErrUnexpectedEOF,
+			matchers: []func(error) bool{isEOF},
+		},
+		"match": {
+			give:      errors.Wrap(io.EOF, "read"),
+			matchers:  []func(error) bool{nil, isEOF},
+			wantFatal: true,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := errors.FatalIf(tt.give, tt.matchers...)
+			require.Equal(t, tt.wantFatal, errors.IsFatal(err))
+			if !tt.wantFatal {
+				require.Equal(t, tt.give, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.give)
+		})
+	}
+}
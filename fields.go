@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"errors"
+)
+
+// WithField returns a new error that wraps err and carries the given
+// key-value field, which can be retrieved with [Fields]. If err is nil,
+// WithField returns nil.
+func WithField(err error, key string, value any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{
+		err:    err,
+		fields: map[string]any{key: value},
+	}
+}
+
+// WithFields returns a new error that wraps err and carries the given
+// key-value fields, which can be retrieved with [Fields]. If err is nil,
+// WithFields returns nil; if fields is empty, err is returned verbatim.
+func WithFields(err error, fields map[string]any) error {
+	if err == nil || len(fields) == 0 {
+		return err
+	}
+
+	tmp := make(map[string]any, len(fields))
+	for k, v := range fields {
+		tmp[k] = v
+	}
+
+	return &fieldsError{
+		err:    err,
+		fields: tmp,
+	}
+}
+
+// Fields returns all fields attached to errors in err's chain, including any
+// joined errors. If the same key is attached more than once, the outermost
+// value takes precedence. If err has no fields, Fields returns nil.
+func Fields(err error) map[string]any {
+	var fields map[string]any
+	collectFields(err, func(key string, value any) {
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		if _, exists := fields[key]; !exists {
+			fields[key] = value
+		}
+	}, newChainGuard())
+	return fields
+}
+
+func collectFields(
+	err error,
+	fn func(key string, value any),
+	guard chainGuard,
+) {
+	for err != nil && guard.visit(err) {
+		if x, ok := err.(*fieldsError); ok {
+			for k, v := range x.fields {
+				fn(k, v)
+			}
+		}
+
+		if x, ok := err.(interface{ Unwrap() []error }); ok {
+			if !guard.expand(err) {
+				return
+			}
+			for _, e := range x.Unwrap() {
+				collectFields(e, fn, guard.branch())
+			}
+			return
+		}
+
+		err = errors.Unwrap(err)
+	}
+}
+
+type fieldsError struct {
+	err    error
+	fields map[string]any
+}
+
+func (e *fieldsError) Unwrap() error {
+	return e.err
+}
+
+func (e *fieldsError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithField(t *testing.T) {
+	var (
+		base = errors.New("foo")
+		err  = errors.WithField(base, "user_id", 42)
+	)
+
+	require.Equal(t, base.Error(), err.Error())
+	require.ErrorIs(t, err, base)
+	require.Equal(t, map[string]any{"user_id": 42}, errors.Fields(err))
+	require.NoError(t, errors.WithField(nil, "user_id", 42))
+}
+
+func TestWithFields(t *testing.T) {
+	var (
+		base   = errors.New("foo")
+		fields = map[string]any{"a": 1, "b": "two"}
+		err    = errors.WithFields(base, fields)
+	)
+
+	fields["c"] = 3 // must not affect err
+	require.Equal(t, map[string]any{"a": 1, "b": "two"}, errors.Fields(err))
+	require.Equal(t, base, errors.WithFields(base, nil))
+	require.NoError(t, errors.WithFields(nil, fields))
+}
+
+func TestFields(t *testing.T) {
+	var (
+		inner = errors.WithFields(errors.New("inner"), map[string]any{
+			"a": "inner",
+			"b": "inner",
+		})
+		outer = errors.WithField(errors.Wrap(inner, "outer"), "a", "outer")
+		other = errors.WithField(errors.New("other"), "c", "other")
+		err   = errors.Join(outer, other)
+	)
+
+	require.Equal(t, map[string]any{
+		"a": "outer",
+		"b": "inner",
+		"c": "other",
+	}, errors.Fields(err))
+
+	require.Nil(t, errors.Fields(nil))
+	require.Nil(t, errors.Fields(errors.New("foo")))
+}
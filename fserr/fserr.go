@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package fserr provides filesystem error classification helpers.
+//
+// Unlike os.IsNotExist and friends, the helpers in this package inspect the
+// entire error chain, and thus work with arbitrarily wrapped errors.
+package fserr
+
+import (
+	"io/fs"
+
+	"go.mway.dev/errors"
+)
+
+// IsExist reports whether err's chain indicates that a file or directory
+// already exists.
+func IsExist(err error) bool {
+	return errors.Is(err, fs.ErrExist)
+}
+
+// IsNotExist reports whether err's chain indicates that a file or directory
+// does not exist.
+func IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// IsPermission reports whether err's chain indicates that permission was
+// denied.
+func IsPermission(err error) bool {
+	return errors.Is(err, fs.ErrPermission)
+}
+
+// Path returns the path of the first *fs.PathError in err's chain, if any.
+func Path(err error) (string, bool) {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		return "", false
+	}
+	return pathErr.Path, true
+}
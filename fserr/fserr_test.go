@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package fserr_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/fserr"
+)
+
+func TestIsNotExist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+
+	_, err := os.Open(path)
+	err = errors.Wrap(fmt.Errorf("load config: %w", err), "start")
+
+	require.True(t, fserr.IsNotExist(err))
+	require.False(t, os.IsNotExist(err))
+	require.False(t, fserr.IsExist(err))
+	require.False(t, fserr.IsPermission(err))
+
+	havePath, ok := fserr.Path(err)
+	require.True(t, ok)
+	require.Equal(t, path, havePath)
+}
+
+func TestIsExist(t *testing.T) {
+	err := errors.Wrap(os.Mkdir(t.TempDir(), 0o700), "create")
+	require.True(t, fserr.IsExist(err))
+	require.False(t, fserr.IsNotExist(err))
+}
+
+func TestIsPermission(t *testing.T) {
+	err := errors.Wrap(&fs.PathError{
+		Op:   "open",
+		Path: "/root/secret",
+		Err:  fs.ErrPermission,
+	}, "read secret")
+
+	require.True(t, fserr.IsPermission(err))
+	require.False(t, fserr.IsNotExist(err))
+
+	havePath, ok := fserr.Path(err)
+	require.True(t, ok)
+	require.Equal(t, "/root/secret", havePath)
+}
+
+func TestPath_NoPath(t *testing.T) {
+	for _, err := range []error{nil, errors.New("foo"), fs.ErrNotExist} {
+		havePath, ok := fserr.Path(err)
+		require.False(t, ok)
+		require.Empty(t, havePath)
+	}
+}
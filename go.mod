@@ -2,14 +2,10 @@ module go.mway.dev/errors
 
 go 1.21
 
-require (
-	github.com/stretchr/testify v1.7.2
-	go.uber.org/multierr v1.8.0
-)
+require github.com/stretchr/testify v1.7.2
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	go.uber.org/atomic v1.7.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package graphqlerr renders errors as GraphQL response errors, and parses
+// GraphQL response errors back into errors.
+//
+// The rendered structure is compatible with the GraphQL specification and
+// with github.com/vektah/gqlparser's gqlerror.Error: the message is derived
+// with errors.SafeMessage, and the error's code and fields are carried in
+// extensions.
+package graphqlerr
+
+import (
+	"strings"
+
+	"go.mway.dev/errors"
+)
+
+// Extension keys.
+const (
+	// CodeKey is the extensions key that carries an error's code, formatted
+	// as an uppercase string (e.g. "NOT_FOUND").
+	CodeKey = "code"
+	// FieldsKey is the extensions key that carries an error's fields.
+	FieldsKey = "fields"
+)
+
+// An Error is a GraphQL response error.
+type Error struct {
+	Extensions map[string]any `json:"extensions,omitempty"`
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+}
+
+// Error returns e's message.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WithPath returns a new error that wraps err and carries the given GraphQL
+// response path, e.g. []any{"users", 3, "email"}. If err is nil, WithPath
+// returns nil.
+func WithPath(err error, path []any) error {
+	if err == nil {
+		return nil
+	}
+	return &pathError{
+		err:  err,
+		path: path,
+	}
+}
+
+// Path returns the outermost GraphQL response path in err's chain, as attached
+// by WithPath or Parse, if any.
+func Path(err error) ([]any, bool) {
+	var pathErr *pathError
+	if !errors.As(err, &pathErr) {
+		return nil, false
+	}
+	return pathErr.path, true
+}
+
+// Render renders err as a GraphQL response error. The message is derived with
+// errors.SafeMessage. The path is taken from err's chain (see WithPath). The
+// extensions contain err's code and fields, if any. If err is nil, Render
+// returns nil.
+func Render(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	code := errors.CodeOf(err)
+	rendered := &Error{
+		Message: errors.SafeMessage(err),
+		Extensions: map[string]any{
+			CodeKey: strings.ToUpper(code.String()),
+		},
+	}
+	rendered.Path, _ = Path(err)
+	if fields := errors.Fields(err); len(fields) > 0 {
+		rendered.Extensions[FieldsKey] = fields
+	}
+
+	return rendered
+}
+
+// Parse reconstructs an error from a GraphQL response error, restoring its
+// code, fields, and path, as rendered by Render. If e is nil, Parse returns
+// nil.
+func Parse(e *Error) error {
+	if e == nil {
+		return nil
+	}
+
+	var err error
+	if str, ok := e.Extensions[CodeKey].(string); ok {
+		code, _ := errors.ParseCode(str)
+		err = errors.NewCoded(code, e.Message)
+	} else {
+		err = errors.New(e.Message)
+	}
+
+	if fields, ok := e.Extensions[FieldsKey].(map[string]any); ok {
+		err = errors.WithFields(err, fields)
+	}
+	if len(e.Path) > 0 {
+		err = WithPath(err, e.Path)
+	}
+
+	return err
+}
+
+type pathError struct {
+	err  error
+	path []any
+}
+
+func (e *pathError) Unwrap() error {
+	return e.err
+}
+
+func (e *pathError) Error() string {
+	return e.err.Error()
+}
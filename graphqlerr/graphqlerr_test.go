@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package graphqlerr_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/graphqlerr"
+)
+
+func TestRender(t *testing.T) {
+	err := errors.NewCoded(errors.CodeNotFound, "sql: no rows in result set")
+	err = errors.WithPublicMessage(err, "user not found")
+	err = errors.WithField(err, "user_id", 42)
+	err = graphqlerr.WithPath(errors.Wrap(err, "resolve user"), []any{"users", 3})
+
+	rendered := graphqlerr.Render(err)
+	require.Equal(t, "user not found", rendered.Error())
+
+	raw, jsonErr := json.Marshal(rendered)
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{
+		"message": "user not found",
+		"path": ["users", 3],
+		"extensions": {
+			"code": "NOT_FOUND",
+			"fields": {"user_id": 42}
+		}
+	}`, string(raw))
+}
+
+func TestRender_Minimal(t *testing.T) {
+	rendered := graphqlerr.Render(errors.New("boom"))
+	require.Equal(t, &graphqlerr.Error{
+		Message: "unknown",
+		Extensions: map[string]any{
+			"code": "UNKNOWN",
+		},
+	}, rendered)
+
+	rendered = graphqlerr.Render(errors.NewCoded(errors.CodeInvalidArgument, "bad id"))
+	require.Equal(t, "bad id", rendered.Message)
+
+	require.Nil(t, graphqlerr.Render(nil))
+}
+
+func TestParse(t *testing.T) {
+	var rendered graphqlerr.Error
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"message": "user not found",
+		"path": ["users", 3],
+		"extensions": {
+			"code": "NOT_FOUND",
+			"fields": {"user_id": 42}
+		}
+	}`), &rendered))
+
+	err := graphqlerr.Parse(&rendered)
+	require.Equal(t, "user not found", err.Error())
+	require.Equal(t, errors.CodeNotFound, errors.CodeOf(err))
+	require.Equal(t, map[string]any{"user_id": float64(42)}, errors.Fields(err))
+
+	path, ok := graphqlerr.Path(err)
+	require.True(t, ok)
+	require.Equal(t, []any{"users", float64(3)}, path)
+
+	require.Equal(t, &rendered, graphqlerr.Render(err))
+}
+
+func TestParse_Minimal(t *testing.T) {
+	err := graphqlerr.Parse(&graphqlerr.Error{Message: "boom"})
+	require.Equal(t, "boom", err.Error())
+	require.Equal(t, errors.CodeUnknown, errors.CodeOf(err))
+
+	_, ok := graphqlerr.Path(err)
+	require.False(t, ok)
+
+	require.NoError(t, graphqlerr.Parse(nil))
+	require.NoError(t, graphqlerr.WithPath(nil, []any{"foo"}))
+}
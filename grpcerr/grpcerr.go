@@ -0,0 +1,182 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package grpcerr provides gRPC server interceptors that convert errors into
+// gRPC statuses.
+package grpcerr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"go.mway.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CorrelationIDField is the field key used to attach correlation IDs to
+// errors (see errors.WithField).
+const CorrelationIDField = "correlation_id"
+
+// Status converts err into a gRPC status. If err's chain contains an error
+// that already has a GRPCStatus() *status.Status method, that status is
+// returned. Otherwise, the status code is derived with errors.CodeOf, and the
+// status message with errors.SafeMessage. Because a non-nil error must not be
+// reported as OK, errors whose code is errors.CodeOK or is not a canonical
+// gRPC code are reported as codes.Unknown. If err is nil, Status returns nil.
+func Status(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return grpcErr.GRPCStatus()
+	}
+
+	code := errors.CodeOf(err)
+	msg := errors.SafeMessage(err)
+	if code == errors.CodeOK || code > errors.CodeUnauthenticated {
+		if _, ok := errors.PublicMessage(err); !ok {
+			msg = errors.CodeUnknown.String()
+		}
+		code = errors.CodeUnknown
+	}
+	return status.New(codes.Code(code), msg)
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that converts
+// errors returned by handlers into gRPC statuses with Status. Each error is
+// annotated with the request's correlation ID, which is also sent to the
+// client as trailer metadata, and is logged with its full chain: at Warn level
+// for client errors (codes that map to a 4xx HTTP status), and at Error level
+// otherwise.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	options := DefaultOptions().With(opts...)
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp any, err error) {
+		if options.Recover {
+			defer func() {
+				if v := recover(); v != nil {
+					err = options.handle(ctx, info.FullMethod, errors.Recover(v), grpc.SetTrailer)
+				}
+			}()
+		}
+
+		resp, err = handler(ctx, req)
+		return resp, options.handle(ctx, info.FullMethod, err, grpc.SetTrailer)
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor that
+// converts errors returned by handlers into gRPC statuses with Status. Each
+// error is annotated with the request's correlation ID, which is also sent to
+// the client as trailer metadata, and is logged with its full chain, at the
+// same levels as UnaryServerInterceptor.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	options := DefaultOptions().With(opts...)
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		setTrailer := func(_ context.Context, md metadata.MD) error {
+			ss.SetTrailer(md)
+			return nil
+		}
+
+		if options.Recover {
+			defer func() {
+				if v := recover(); v != nil {
+					err = options.handle(
+						ss.Context(),
+						info.FullMethod,
+						errors.Recover(v),
+						setTrailer,
+					)
+				}
+			}()
+		}
+
+		return options.handle(ss.Context(), info.FullMethod, handler(srv, ss), setTrailer)
+	}
+}
+
+func (o Options) handle(
+	ctx context.Context,
+	method string,
+	err error,
+	setTrailer func(context.Context, metadata.MD) error,
+) error {
+	if err == nil {
+		return nil
+	}
+
+	if len(o.CorrelationHeader) > 0 {
+		id := correlationID(ctx, o.CorrelationHeader)
+		err = errors.WithField(err, CorrelationIDField, id)
+		_ = setTrailer(ctx, metadata.Pairs(o.CorrelationHeader, id)) //nolint:errcheck
+	}
+
+	st := Status(err)
+	if o.Logger != nil {
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.String("code", st.Code().String()),
+			slog.String("error", err.Error()),
+		}
+		for k, v := range errors.Fields(err) {
+			attrs = append(attrs, slog.Any(k, v))
+		}
+		o.Logger.LogAttrs(ctx, logLevel(st.Code()), "grpc request failed", attrs...)
+	}
+
+	return st.Err()
+}
+
+func logLevel(code codes.Code) slog.Level {
+	status := errors.Code(code).HTTPStatus()
+	if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
+
+func correlationID(ctx context.Context, header string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(header); len(ids) > 0 && len(ids[0]) > 0 {
+			return ids[0]
+		}
+	}
+
+	var buf [16]byte
+	_, _ = rand.Read(buf[:]) //nolint:errcheck
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package grpcerr_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/grpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var _unaryInfo = &grpc.UnaryServerInfo{FullMethod: "/test.Service/Unary"}
+
+func TestStatus(t *testing.T) {
+	require.Nil(t, grpcerr.Status(nil))
+
+	st := grpcerr.Status(errors.Wrap(errors.NewCoded(errors.CodeNotFound, "not found"), "get"))
+	require.Equal(t, codes.NotFound, st.Code())
+	require.Equal(t, "get: not found", st.Message())
+
+	st = grpcerr.Status(errors.WithPublicMessage(errors.New("pq: boom"), "try again"))
+	require.Equal(t, codes.Unknown, st.Code())
+	require.Equal(t, "try again", st.Message())
+
+	st = grpcerr.Status(errors.Wrap(errors.NewCoded(errors.CodeInternal, "pq: boom"), "query"))
+	require.Equal(t, codes.Internal, st.Code())
+	require.Equal(t, "internal", st.Message())
+
+	st = grpcerr.Status(errors.NewCoded(errors.CodeOK, "pq: boom"))
+	require.Equal(t, codes.Unknown, st.Code())
+	require.Equal(t, "unknown", st.Message())
+
+	st = grpcerr.Status(errors.NewCoded(errors.Code(99), "pq: boom"))
+	require.Equal(t, codes.Unknown, st.Code())
+	require.Equal(t, "unknown", st.Message())
+
+	st = grpcerr.Status(errors.WithPublicMessage(
+		errors.NewCoded(errors.CodeOK, "pq: boom"),
+		"try again",
+	))
+	require.Equal(t, codes.Unknown, st.Code())
+	require.Equal(t, "try again", st.Message())
+
+	existing := status.Error(codes.Aborted, "aborted")
+	st = grpcerr.Status(errors.Wrap(existing, "wrapped"))
+	require.Equal(t, codes.Aborted, st.Code())
+	require.Equal(t, "aborted", st.Message())
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var (
+		logs        bytes.Buffer
+		stream      = &transportStream{}
+		interceptor = grpcerr.UnaryServerInterceptor(
+			grpcerr.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		)
+		ctx = grpc.NewContextWithServerTransportStream(
+			metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs(grpcerr.DefaultCorrelationHeader, "abc123"),
+			),
+			stream,
+		)
+	)
+
+	_, err := interceptor(ctx, nil, _unaryInfo, func(context.Context, any) (any, error) {
+		return nil, errors.WithField(
+			errors.NewCoded(errors.CodeUnavailable, "backend down"),
+			"shard",
+			7,
+		)
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unavailable, st.Code())
+	require.Equal(t, "unavailable", st.Message())
+	require.Equal(t, []string{"abc123"}, stream.trailer.Get(grpcerr.DefaultCorrelationHeader))
+
+	require.Contains(t, logs.String(), "level=ERROR")
+	require.Contains(t, logs.String(), "method=/test.Service/Unary")
+	require.Contains(t, logs.String(), "code=Unavailable")
+	require.Contains(t, logs.String(), `error="backend down"`)
+	require.Contains(t, logs.String(), "correlation_id=abc123")
+	require.Contains(t, logs.String(), "shard=7")
+}
+
+func TestUnaryServerInterceptor_ClientErrorLogsWarning(t *testing.T) {
+	var (
+		logs        bytes.Buffer
+		interceptor = grpcerr.UnaryServerInterceptor(
+			grpcerr.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		)
+		ctx = grpc.NewContextWithServerTransportStream(
+			context.Background(),
+			&transportStream{},
+		)
+	)
+
+	_, err := interceptor(ctx, nil, _unaryInfo, func(context.Context, any) (any, error) {
+		return nil, errors.NewCoded(errors.CodeNotFound, "user 42")
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Contains(t, logs.String(), "level=WARN")
+	require.Contains(t, logs.String(), "code=NotFound")
+}
+
+func TestUnaryServerInterceptor_Success(t *testing.T) {
+	interceptor := grpcerr.UnaryServerInterceptor(grpcerr.WithLogger(nil))
+	resp, err := interceptor(
+		context.Background(),
+		"req",
+		_unaryInfo,
+		func(_ context.Context, req any) (any, error) {
+			return req, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "req", resp)
+}
+
+func TestUnaryServerInterceptor_GeneratedCorrelationID(t *testing.T) {
+	var (
+		stream      = &transportStream{}
+		interceptor = grpcerr.UnaryServerInterceptor(grpcerr.WithLogger(nil))
+		ctx         = grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	)
+
+	_, err := interceptor(ctx, nil, _unaryInfo, func(context.Context, any) (any, error) {
+		return nil, errors.New("boom")
+	})
+	require.Equal(t, codes.Unknown, status.Code(err))
+	require.Len(t, stream.trailer.Get(grpcerr.DefaultCorrelationHeader), 1)
+	require.Len(t, stream.trailer.Get(grpcerr.DefaultCorrelationHeader)[0], 32)
+}
+
+func TestUnaryServerInterceptor_Recover(t *testing.T) {
+	interceptor := grpcerr.UnaryServerInterceptor(
+		grpcerr.WithLogger(nil),
+		grpcerr.WithCorrelationHeader(""),
+		grpcerr.WithRecover(true),
+	)
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		_unaryInfo,
+		func(context.Context, any) (any, error) {
+			panic("boom")
+		},
+	)
+	require.Equal(t, codes.Unknown, status.Code(err))
+	require.Equal(t, "unknown", status.Convert(err).Message())
+
+	interceptor = grpcerr.UnaryServerInterceptor(grpcerr.WithLogger(nil))
+	require.Panics(t, func() {
+		_, _ = interceptor( //nolint:errcheck
+			context.Background(),
+			nil,
+			_unaryInfo,
+			func(context.Context, any) (any, error) {
+				panic("boom")
+			},
+		)
+	})
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	var (
+		stream = &serverStream{
+			ctx: metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs("x-trace", "trace-1"),
+			),
+		}
+		info        = &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+		interceptor = grpcerr.StreamServerInterceptor(
+			grpcerr.WithLogger(nil),
+			grpcerr.WithCorrelationHeader("x-trace"),
+			grpcerr.WithRecover(true),
+		)
+	)
+
+	err := interceptor(nil, stream, info, func(any, grpc.ServerStream) error {
+		return errors.NewCoded(errors.CodePermissionDenied, "denied")
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Equal(t, []string{"trace-1"}, stream.trailer.Get("x-trace"))
+
+	err = interceptor(nil, stream, info, func(any, grpc.ServerStream) error {
+		panic("boom")
+	})
+	require.Equal(t, codes.Unknown, status.Code(err))
+
+	err = interceptor(nil, stream, info, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+type transportStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *transportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	trailer metadata.MD
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package grpcerr
+
+import (
+	"log/slog"
+)
+
+// DefaultCorrelationHeader is the default metadata key used to propagate
+// correlation IDs.
+const DefaultCorrelationHeader = "x-request-id"
+
+// Options are used to configure interceptors.
+type Options struct {
+	// Logger is used to log errors. If nil, errors are not logged.
+	Logger *slog.Logger
+	// CorrelationHeader is the metadata key from which correlation IDs are
+	// read, and to which they are written as trailers. If a request does not
+	// have a correlation ID, one is generated. If empty, correlation IDs are
+	// not attached.
+	CorrelationHeader string
+	// Recover controls whether panics in handlers are recovered and returned
+	// as errors.
+	Recover bool
+}
+
+// DefaultOptions returns a new Options with sane defaults.
+func DefaultOptions() Options {
+	return Options{
+		Logger:            slog.Default(),
+		CorrelationHeader: DefaultCorrelationHeader,
+		Recover:           false,
+	}
+}
+
+// With returns a new Options, using the current Options as a base and merging
+// the given options down onto it.
+func (o Options) With(opts ...Option) Options {
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return o
+}
+
+func (o Options) apply(opts *Options) {
+	opts.Logger = o.Logger
+	opts.CorrelationHeader = o.CorrelationHeader
+	opts.Recover = o.Recover
+}
+
+// An Option configures an interceptor.
+type Option interface {
+	apply(*Options)
+}
+
+type optionFunc func(*Options)
+
+func (f optionFunc) apply(o *Options) {
+	f(o)
+}
+
+// WithLogger returns an Option that configures an interceptor to log errors
+// to the given logger. If logger is nil, errors are not logged.
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(o *Options) {
+		o.Logger = logger
+	})
+}
+
+// WithCorrelationHeader returns an Option that configures the metadata key
+// used to propagate correlation IDs. If header is empty, correlation IDs are
+// not attached.
+func WithCorrelationHeader(header string) Option {
+	return optionFunc(func(o *Options) {
+		o.CorrelationHeader = header
+	})
+}
+
+// WithRecover returns an Option that configures whether an interceptor
+// recovers panics.
+func WithRecover(enabled bool) Option {
+	return optionFunc(func(o *Options) {
+		o.Recover = enabled
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package grpcerr_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors/grpcerr"
+)
+
+func TestOptionsWith(t *testing.T) {
+	var (
+		logger  = slog.New(slog.Default().Handler())
+		base    = grpcerr.DefaultOptions()
+		updated = base.With(
+			grpcerr.WithLogger(logger),
+			grpcerr.WithCorrelationHeader("x-trace"),
+			grpcerr.WithRecover(true),
+		)
+		merged = base.With(updated)
+	)
+
+	require.Equal(t, slog.Default(), base.Logger)
+	require.Equal(t, grpcerr.DefaultCorrelationHeader, base.CorrelationHeader)
+	require.False(t, base.Recover)
+
+	require.Equal(t, logger, updated.Logger)
+	require.Equal(t, "x-trace", updated.CorrelationHeader)
+	require.True(t, updated.Recover)
+	require.Equal(t, updated, merged)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"hash/maphash"
+	"reflect"
+)
+
+var _hashSeed = maphash.MakeSeed()
+
+// Hash returns a hash of err based on its message and the types of the errors
+// in its chain, e.g. for use as a map key or for deduplication or sampling
+// decisions that would otherwise compare full messages. Errors with the same
+// message and chain types have the same hash. Hashes are stable within a
+// process, but not across processes, and must not be persisted. If err is
+// nil, Hash returns 0.
+func Hash(err error) uint64 {
+	if LazyOrNil(err) == nil {
+		return 0
+	}
+
+	var h maphash.Hash
+	h.SetSeed(_hashSeed)
+	h.WriteString(err.Error())
+
+	findInChain(err, func(err error) bool {
+		h.WriteByte(0)
+		h.WriteString(reflect.TypeOf(err).String())
+		return false
+	}, newChainGuard())
+
+	return h.Sum64()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestHash(t *testing.T) {
+	require.Zero(t, errors.Hash(nil))
+	require.Zero(t, errors.Hash(errors.Lazy(func() error { return nil })))
+
+	var (
+		a = errors.Wrap(errors.New("foo"), "wrap")
+		b = errors.Wrap(errors.New("foo"), "wrap")
+	)
+	require.NotZero(t, errors.Hash(a))
+	require.Equal(t, errors.Hash(a), errors.Hash(b))
+	require.Equal(t, errors.Hash(a), errors.Hash(a))
+
+	cases := map[string]error{
+		"message":   errors.Wrap(errors.New("bar"), "wrap"),
+		"types":     fmt.Errorf("wrap: %w", errors.New("foo")),
+		"unwrapped": errors.New("wrap: foo"),
+		"other":     io.EOF,
+	}
+
+	for name, err := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NotEqual(t, errors.Hash(a), errors.Hash(err))
+		})
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	err := errors.Wrap(errors.Join(io.EOF, io.ErrUnexpectedEOF), "wrap")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = errors.Hash(err)
+	}
+}
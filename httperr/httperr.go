@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package httperr provides net/http middleware that renders errors returned
+// by handlers.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.mway.dev/errors"
+)
+
+// A HandlerFunc is an HTTP handler that returns an error.
+type HandlerFunc = func(w http.ResponseWriter, r *http.Request) error
+
+// Handler returns an http.Handler that calls fn and renders any error it
+// returns. For each non-nil error, Handler:
+//
+//   - derives the response status with errors.HTTPStatus;
+//   - reports the error with errors.Report, using the request's context;
+//   - renders the error as RFC 7807 problem details (or as JSON, see
+//     WithFormat), unless fn has already written a response header.
+//
+// Unless configured otherwise with WithRecover, panics in fn are recovered
+// with errors.Recover and handled as errors; http.ErrAbortHandler is always
+// re-panicked.
+//
+// Rendered messages are derived with errors.SafeMessage.
+func Handler(fn HandlerFunc, opts ...Option) http.Handler {
+	options := DefaultOptions().With(opts...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		if err := call(fn, rw, r, options.Recover); err != nil {
+			errors.Report(r.Context(), err)
+			if !rw.wroteHeader {
+				render(rw, r, err, options.Format)
+			}
+		}
+	})
+}
+
+func call(
+	fn HandlerFunc,
+	w http.ResponseWriter,
+	r *http.Request,
+	recoverPanics bool,
+) (err error) {
+	if recoverPanics {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				err = errors.Recover(v)
+			}
+		}()
+	}
+	return fn(w, r)
+}
+
+// A Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Status   int    `json:"status"`
+}
+
+// A JSONError is the body rendered by FormatJSON.
+type JSONError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func render(w http.ResponseWriter, r *http.Request, err error, format Format) {
+	var (
+		status = errors.HTTPStatus(err)
+		code   = errors.CodeOf(err).String()
+		msg    = errors.SafeMessage(err)
+		body   any
+	)
+
+	switch format {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+		body = JSONError{
+			Code:    code,
+			Message: msg,
+		}
+	default:
+		w.Header().Set("Content-Type", "application/problem+json")
+		body = Problem{
+			Type:     "about:blank",
+			Title:    title(status),
+			Status:   status,
+			Detail:   msg,
+			Instance: r.URL.Path,
+			Code:     code,
+		}
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body) //nolint:errcheck
+}
+
+// title returns the title of status for problem details. Statuses without a
+// standard status text, e.g. the 499 used for errors.CodeCanceled, are given
+// a descriptive fallback so that the title is never empty.
+func title(status int) string {
+	if text := http.StatusText(status); len(text) > 0 {
+		return text
+	}
+	if status == statusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return "Status " + strconv.Itoa(status)
+}
+
+// statusClientClosedRequest is the non-standard status used when the client
+// closed the request before the server responded.
+const statusClientClosedRequest = 499
+
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package httperr_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/httperr"
+)
+
+func TestHandler_Problem(t *testing.T) {
+	reported := captureReports(t)
+	handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+		return errors.Wrap(errors.NewCoded(errors.CodeNotFound, "user not found"), "get user")
+	})
+
+	rec := serve(handler, "/users/42")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "get user: user not found",
+		"instance": "/users/42",
+		"code": "not_found"
+	}`, rec.Body.String())
+	require.Len(t, *reported, 1)
+}
+
+func TestHandler_JSON(t *testing.T) {
+	captureReports(t)
+	handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+		return errors.WithPublicMessage(errors.New("pq: connection refused"), "try again")
+	}, httperr.WithFormat(httperr.FormatJSON))
+
+	rec := serve(handler, "/")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"code": "unknown", "message": "try again"}`, rec.Body.String())
+}
+
+func TestHandler_ServerErrorHidesMessage(t *testing.T) {
+	captureReports(t)
+	handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+		return errors.New("pq: connection refused")
+	})
+
+	rec := serve(handler, "/")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NotContains(t, rec.Body.String(), "pq")
+	require.Contains(t, rec.Body.String(), `"detail":"unknown"`)
+}
+
+func TestHandler_NonStandardStatus(t *testing.T) {
+	captureReports(t)
+
+	cases := map[string]struct {
+		give      error
+		wantTitle string
+		want      int
+	}{
+		"canceled": {
+			give:      errors.NewCoded(errors.CodeCanceled, "canceled"),
+			want:      499,
+			wantTitle: "Client Closed Request",
+		},
+		"unknown": {
+			give:      statusError(599),
+			want:      599,
+			wantTitle: "Status 599",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+				return tt.give
+			})
+
+			rec := serve(handler, "/")
+			require.Equal(t, tt.want, rec.Code)
+
+			var problem httperr.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			require.Equal(t, tt.wantTitle, problem.Title)
+		})
+	}
+}
+
+type statusError int
+
+func (e statusError) Error() string {
+	return "status " + strconv.Itoa(int(e))
+}
+
+func (e statusError) HTTPStatus() int {
+	return int(e)
+}
+
+func TestHandler_NoError(t *testing.T) {
+	reported := captureReports(t)
+	handler := httperr.Handler(func(w http.ResponseWriter, _ *http.Request) error {
+		_, err := io.WriteString(w, "ok")
+		return err
+	})
+
+	rec := serve(handler, "/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ok", rec.Body.String())
+	require.Empty(t, *reported)
+}
+
+func TestHandler_AlreadyWritten(t *testing.T) {
+	reported := captureReports(t)
+	handler := httperr.Handler(func(w http.ResponseWriter, _ *http.Request) error {
+		w.WriteHeader(http.StatusAccepted)
+		return errors.New("late failure")
+	})
+
+	rec := serve(handler, "/")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Len(t, *reported, 1)
+}
+
+func TestHandler_Panic(t *testing.T) {
+	reported := captureReports(t)
+	handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+		panic("boom")
+	})
+
+	rec := serve(handler, "/")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Len(t, *reported, 1)
+
+	var panicErr *errors.PanicError
+	require.True(t, errors.As((*reported)[0], &panicErr))
+	require.Equal(t, "boom", panicErr.Value)
+}
+
+func TestHandler_PanicNotRecovered(t *testing.T) {
+	captureReports(t)
+	handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+		panic("boom")
+	}, httperr.WithRecover(false))
+
+	require.PanicsWithValue(t, "boom", func() {
+		serve(handler, "/")
+	})
+}
+
+func TestHandler_AbortHandler(t *testing.T) {
+	captureReports(t)
+	handler := httperr.Handler(func(http.ResponseWriter, *http.Request) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		serve(handler, "/")
+	})
+}
+
+func serve(handler http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func captureReports(t *testing.T) *[]error {
+	var reported []error
+	t.Cleanup(errors.SetReporter(func(_ context.Context, err error) {
+		reported = append(reported, err)
+	}))
+	return &reported
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package httperr
+
+// A Format is a response body format used to render errors.
+type Format int
+
+// Response body formats.
+const (
+	// FormatProblem renders errors as RFC 7807 problem details, using the
+	// application/problem+json content type.
+	FormatProblem Format = iota
+	// FormatJSON renders errors as a JSON object with code and message
+	// members, using the application/json content type.
+	FormatJSON
+)
+
+// Options are used to configure a Handler.
+type Options struct {
+	// Format is the response body format used to render errors.
+	Format Format
+	// Recover controls whether panics in handlers are recovered and rendered
+	// as errors.
+	Recover bool
+}
+
+// DefaultOptions returns a new Options with sane defaults.
+func DefaultOptions() Options {
+	return Options{
+		Format:  FormatProblem,
+		Recover: true,
+	}
+}
+
+// With returns a new Options, using the current Options as a base and merging
+// the given options down onto it.
+func (o Options) With(opts ...Option) Options {
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return o
+}
+
+func (o Options) apply(opts *Options) {
+	opts.Format = o.Format
+	opts.Recover = o.Recover
+}
+
+// An Option configures a Handler.
+type Option interface {
+	apply(*Options)
+}
+
+type optionFunc func(*Options)
+
+func (f optionFunc) apply(o *Options) {
+	f(o)
+}
+
+// WithFormat returns an Option that configures a Handler to render errors
+// using the given format.
+func WithFormat(format Format) Option {
+	return optionFunc(func(o *Options) {
+		o.Format = format
+	})
+}
+
+// WithRecover returns an Option that configures whether a Handler recovers
+// panics.
+func WithRecover(enabled bool) Option {
+	return optionFunc(func(o *Options) {
+		o.Recover = enabled
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A palette holds the ANSI escape sequences with which Humanize's renderings
+// are colored. The zero value renders without color.
+type palette struct {
+	err   string
+	hint  string
+	frame string
+	reset string
+}
+
+var _ansiPalette = palette{
+	err:   "\x1b[31m",
+	hint:  "\x1b[33m",
+	frame: "\x1b[2m",
+	reset: "\x1b[0m",
+}
+
+// paint writes s to b using the given color, if any.
+func (p palette) paint(b *strings.Builder, color string, s string) {
+	if len(color) == 0 {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(s)
+	b.WriteString(p.reset)
+}
+
+// WithHint returns a new error that wraps err and carries a hint describing
+// how the failure might be resolved, e.g. "run with sudo", which can be
+// retrieved with [Hints] and is included by [Humanize]. The returned error's
+// message is identical to err's. If err is nil, WithHint returns nil; if hint
+// is empty, err is returned verbatim.
+func WithHint(err error, hint string) error {
+	if err == nil || len(hint) == 0 {
+		return err
+	}
+	return &hintError{
+		err:  err,
+		hint: hint,
+	}
+}
+
+// Hints returns the hints attached to errors in err's chain, including any
+// joined errors, in depth-first order (see [Walk]). If err has no hints,
+// Hints returns nil.
+func Hints(err error) []string {
+	var hints []string
+	Walk(err, func(err error) bool {
+		if x, ok := err.(*hintError); ok {
+			hints = append(hints, x.hint)
+		}
+		return true
+	})
+	return hints
+}
+
+// Humanize returns a multi-line rendering of err intended to be read by
+// people, e.g. when a command-line tool fails, as opposed to the compact
+// single-line form intended for logs. The outermost message is prefixed by
+// "Error: ", each layer of context added by wrapping is shown on its own
+// indented "caused by:" line, joined errors are listed individually, and any
+// hints (see [WithHint]) are shown last. For example:
+//
+//	Error: could not load config
+//	  caused by: open /etc/app.yaml: permission denied
+//	  hint: run with sudo
+//
+// An error that wraps no other errors is not shown separately from the error
+// that wraps it, since its message alone, e.g. "permission denied", usually
+// lacks context. If err is nil, Humanize returns an empty string.
+func Humanize(err error) string {
+	return humanize(err, palette{})
+}
+
+// HumanizeColor is like [Humanize], but colors its rendering using ANSI escape
+// sequences for display in a terminal: error messages are red, hints are
+// yellow, and the labels and markers that frame them are dim.
+func HumanizeColor(err error) string {
+	return humanize(err, _ansiPalette)
+}
+
+// WriteHumanized writes the rendering of err produced by [Humanize], followed
+// by a newline, to w. The rendering is colored as by [HumanizeColor] if w is a
+// terminal, unless the NO_COLOR environment variable is set or the TERM
+// environment variable is "dumb". If err is nil, nothing is written.
+func WriteHumanized(w io.Writer, err error) error {
+	if LazyOrNil(err) == nil {
+		return nil
+	}
+
+	var p palette
+	if useColor(w) {
+		p = _ansiPalette
+	}
+
+	_, werr := io.WriteString(w, humanize(err, p)+"\n")
+	return werr
+}
+
+func useColor(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func humanize(err error, p palette) string {
+	node := Describe(err)
+	if node == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	p.paint(&b, p.err, "Error:")
+	b.WriteByte(' ')
+	humanizeNode(&b, p, node, "  ")
+	for _, hint := range Hints(err) {
+		b.WriteString("\n  ")
+		p.paint(&b, p.frame, "hint:")
+		b.WriteByte(' ')
+		p.paint(&b, p.hint, hint)
+	}
+	return b.String()
+}
+
+// humanizeNode writes node's chain to b, indenting each line after the first
+// by indent.
+func humanizeNode(b *strings.Builder, p palette, node *Node, indent string) {
+	for {
+		switch len(node.Causes) {
+		case 0:
+			p.paint(b, p.err, node.Message)
+			return
+		case 1:
+			cause := node.Causes[0]
+			msg, ok := strings.CutSuffix(node.Message, ": "+cause.Message)
+			if ok && len(cause.Causes) == 0 {
+				p.paint(b, p.err, node.Message)
+				return
+			}
+			if !ok {
+				msg = node.Message
+			}
+
+			p.paint(b, p.err, msg)
+			b.WriteString("\n" + indent)
+			p.paint(b, p.frame, "caused by:")
+			b.WriteByte(' ')
+			node = cause
+		default:
+			p.paint(b, p.err, strconv.Itoa(len(node.Causes))+" errors occurred:")
+			for _, cause := range node.Causes {
+				b.WriteString("\n" + indent)
+				p.paint(b, p.frame, "-")
+				b.WriteByte(' ')
+				humanizeNode(b, p, cause, indent+"  ")
+			}
+			return
+		}
+	}
+}
+
+type hintError struct {
+	err  error
+	hint string
+}
+
+func (e *hintError) Unwrap() error {
+	return e.err
+}
+
+func (e *hintError) Error() string {
+	return e.err.Error()
+}
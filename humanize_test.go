@@ -0,0 +1,185 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithHint(t *testing.T) {
+	require.NoError(t, errors.WithHint(nil, "hint"))
+	require.Equal(t, io.EOF, errors.WithHint(io.EOF, ""))
+
+	err := errors.WithHint(io.EOF, "retry")
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, []string{"retry"}, errors.Hints(err))
+
+	err = errors.Join(
+		errors.WithHint(errors.Wrap(err, "read"), "check the input"),
+		errors.WithHint(io.ErrUnexpectedEOF, "try again later"),
+	)
+	require.Equal(t, []string{"check the input", "retry", "try again later"}, errors.Hints(err))
+	require.Nil(t, errors.Hints(io.EOF))
+}
+
+func TestHumanize(t *testing.T) {
+	pathErr := &fs.PathError{
+		Op:   "open",
+		Path: "/etc/app.yaml",
+		Err:  syscall.EACCES,
+	}
+
+	cases := map[string]struct {
+		give error
+		want string
+	}{
+		"nil": {
+			give: nil,
+			want: "",
+		},
+		"leaf": {
+			give: io.EOF,
+			want: "Error: EOF",
+		},
+		"wrapped leaf": {
+			give: errors.Wrap(io.EOF, "read header"),
+			want: "Error: read header: EOF",
+		},
+		"chain": {
+			give: errors.WithHint(
+				errors.Wrap(pathErr, "could not load config"),
+				"run with sudo",
+			),
+			want: "Error: could not load config\n" +
+				"  caused by: open /etc/app.yaml: permission denied\n" +
+				"  hint: run with sudo",
+		},
+		"nested": {
+			give: errors.Wrap(fmt.Errorf("parse: %w", errors.Wrap(io.EOF, "read")), "load"),
+			want: "Error: load\n" +
+				"  caused by: parse\n" +
+				"  caused by: read: EOF",
+		},
+		"unrelated message": {
+			give: errors.WithHint(&customError{err: errors.Wrap(io.EOF, "read")}, "retry"),
+			want: "Error: custom failure\n" +
+				"  caused by: read: EOF\n" +
+				"  hint: retry",
+		},
+		"top-level join": {
+			give: errors.WithHint(errors.Join(io.EOF, errors.Wrap(io.EOF, "read")), "retry"),
+			want: "Error: 2 errors occurred:\n" +
+				"  - EOF\n" +
+				"  - read: EOF\n" +
+				"  hint: retry",
+		},
+		"joined": {
+			give: errors.Wrap(
+				errors.Join(
+					errors.Wrap(pathErr, "load config"),
+					errors.Wrap(errors.Wrap(io.EOF, "read"), "load data"),
+				),
+				"startup",
+			),
+			want: "Error: startup\n" +
+				"  caused by: 2 errors occurred:\n" +
+				"  - load config\n" +
+				"    caused by: open /etc/app.yaml: permission denied\n" +
+				"  - load data\n" +
+				"    caused by: read: EOF",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.Humanize(tt.give))
+		})
+	}
+}
+
+func TestHumanizeColor(t *testing.T) {
+	require.Empty(t, errors.HumanizeColor(nil))
+
+	err := errors.WithHint(
+		errors.Join(io.EOF, errors.Wrap(fmt.Errorf("parse: %w", io.EOF), "load")),
+		"retry",
+	)
+	require.Equal(
+		t,
+		"\x1b[31mError:\x1b[0m \x1b[31m2 errors occurred:\x1b[0m\n"+
+			"  \x1b[2m-\x1b[0m \x1b[31mEOF\x1b[0m\n"+
+			"  \x1b[2m-\x1b[0m \x1b[31mload\x1b[0m\n"+
+			"    \x1b[2mcaused by:\x1b[0m \x1b[31mparse: EOF\x1b[0m\n"+
+			"  \x1b[2mhint:\x1b[0m \x1b[33mretry\x1b[0m",
+		errors.HumanizeColor(err),
+	)
+}
+
+func TestWriteHumanized(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, errors.WriteHumanized(&buf, nil))
+	require.Empty(t, buf.String())
+
+	err := errors.WithHint(errors.Wrap(io.EOF, "read"), "retry")
+	require.NoError(t, errors.WriteHumanized(&buf, err))
+	require.Equal(t, errors.Humanize(err)+"\n", buf.String())
+
+	// Regular files are not terminals.
+	f, ferr := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, ferr)
+	defer f.Close() //nolint:errcheck
+
+	require.NoError(t, errors.WriteHumanized(f, err))
+	raw, ferr := os.ReadFile(f.Name())
+	require.NoError(t, ferr)
+	require.Equal(t, errors.Humanize(err)+"\n", string(raw))
+
+	require.Error(t, errors.WriteHumanized(errWriter{}, err))
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+type customError struct {
+	err error
+}
+
+func (e *customError) Unwrap() error {
+	return e.err
+}
+
+func (e *customError) Error() string {
+	return "custom failure"
+}
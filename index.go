@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"sort"
+	"strconv"
+)
+
+// WithIndex returns a new error that wraps err and records the index of the
+// input element for which err occurred, e.g. in a batch, which can be
+// retrieved with [Index]. The error's message is prefixed with the index,
+// e.g. "index 3: <error>". If err is nil, WithIndex returns nil.
+func WithIndex(err error, i int) error {
+	if err == nil {
+		return nil
+	}
+	return &indexError{
+		err:   err,
+		index: i,
+	}
+}
+
+// Index returns the index recorded by the first error in err's chain created
+// by [WithIndex], if any.
+func Index(err error) (int, bool) {
+	x, ok := AsType[*indexError](err)
+	if !ok {
+		return 0, false
+	}
+	return x.index, true
+}
+
+// JoinIndexed returns an error joining the non-nil errors in errs, each
+// annotated with its key using [WithIndex], in ascending order of index. If
+// errs contains no non-nil errors, JoinIndexed returns nil; if it contains
+// one, the annotated error is returned.
+func JoinIndexed(errs map[int]error) error {
+	indexes := make([]int, 0, len(errs))
+	for i, err := range errs {
+		if err != nil {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+
+	switch len(indexes) {
+	case 0:
+		return nil
+	case 1:
+		return WithIndex(errs[indexes[0]], indexes[0])
+	}
+
+	joined := make([]error, len(indexes))
+	for n, i := range indexes {
+		joined[n] = WithIndex(errs[i], i)
+	}
+	return Join(joined...)
+}
+
+type indexError struct {
+	err   error
+	index int
+}
+
+func (e *indexError) Unwrap() error {
+	return e.err
+}
+
+func (e *indexError) Error() string {
+	return "index " + strconv.Itoa(e.index) + ": " + e.err.Error()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithIndex(t *testing.T) {
+	require.NoError(t, errors.WithIndex(nil, 1))
+
+	err := errors.WithIndex(io.EOF, 3)
+	require.EqualError(t, err, "index 3: EOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	i, ok := errors.Index(fmt.Errorf("batch: %w", err))
+	require.True(t, ok)
+	require.Equal(t, 3, i)
+
+	i, ok = errors.Index(io.EOF)
+	require.False(t, ok)
+	require.Zero(t, i)
+
+	i, ok = errors.Index(errors.Join(io.ErrUnexpectedEOF, errors.WithIndex(io.EOF, 5)))
+	require.True(t, ok)
+	require.Equal(t, 5, i)
+}
+
+func TestJoinIndexed(t *testing.T) {
+	var (
+		errA = errors.New("a")
+		errB = errors.New("b")
+	)
+
+	require.NoError(t, errors.JoinIndexed(nil))
+	require.NoError(t, errors.JoinIndexed(map[int]error{1: nil}))
+
+	err := errors.JoinIndexed(map[int]error{4: errA, 1: nil})
+	require.EqualError(t, err, "index 4: a")
+
+	err = errors.JoinIndexed(map[int]error{7: errB, 2: errA, 3: nil})
+	require.EqualError(t, err, "index 2: a\nindex 7: b")
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+
+	var indexes []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		i, ok := errors.Index(e)
+		require.True(t, ok)
+		indexes = append(indexes, i)
+	}
+	require.Equal(t, []int{2, 7}, indexes)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+// A Translator renders localized messages. Translators are typically bound to
+// a particular locale, e.g. the locale of an incoming request.
+type Translator interface {
+	// Translate returns the localized message for key, interpolating args as
+	// appropriate. If no translation exists, Translate returns false.
+	Translate(key string, args ...any) (string, bool)
+}
+
+// A TranslatorFunc is a function that implements [Translator].
+type TranslatorFunc func(key string, args ...any) (string, bool)
+
+// Translate calls f(key, args...).
+func (f TranslatorFunc) Translate(key string, args ...any) (string, bool) {
+	return f(key, args...)
+}
+
+// WithLocalized returns a new error that wraps err and carries a localization
+// key and arguments, which can be rendered by [Localize] for user-facing
+// output. The returned error's message and chain are identical to err's,
+// preserving the canonical message for logging.
+//
+// If err is nil, WithLocalized returns nil.
+func WithLocalized(err error, key string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &localizedError{
+		err:  err,
+		key:  key,
+		args: args,
+	}
+}
+
+// LocalizedKey returns the localization key and arguments of the first error
+// in err's chain created by [WithLocalized], if any.
+func LocalizedKey(err error) (key string, args []any, ok bool) {
+	var localized *localizedError
+	if !As(err, &localized) {
+		return "", nil, false
+	}
+	return localized.key, localized.args, true
+}
+
+// Localize renders err using t. The first localization key in err's chain, as
+// attached by [WithLocalized], is translated; if err has no localization key,
+// if t is nil, or if t has no translation for the key, err's canonical message
+// is returned instead. If err is nil, Localize returns an empty string.
+func Localize(err error, t Translator) string {
+	if err == nil {
+		return ""
+	}
+
+	if key, args, ok := LocalizedKey(err); ok && t != nil {
+		if msg, ok := t.Translate(key, args...); ok {
+			return msg
+		}
+	}
+
+	return err.Error()
+}
+
+type localizedError struct {
+	err  error
+	key  string
+	args []any
+}
+
+func (e *localizedError) Unwrap() error {
+	return e.err
+}
+
+func (e *localizedError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithLocalized(t *testing.T) {
+	var (
+		base = errors.New("user not found")
+		err  = errors.WithLocalized(base, "user.not_found", "alice")
+	)
+
+	require.Equal(t, base.Error(), err.Error())
+	require.ErrorIs(t, err, base)
+	require.NoError(t, errors.WithLocalized(nil, "foo"))
+
+	key, args, ok := errors.LocalizedKey(errors.Wrap(err, "lookup"))
+	require.True(t, ok)
+	require.Equal(t, "user.not_found", key)
+	require.Equal(t, []any{"alice"}, args)
+
+	_, _, ok = errors.LocalizedKey(base)
+	require.False(t, ok)
+}
+
+func TestLocalize(t *testing.T) {
+	spanish := errors.TranslatorFunc(func(key string, args ...any) (string, bool) {
+		if key != "user.not_found" {
+			return "", false
+		}
+		return fmt.Sprintf("usuario %v no encontrado", args...), true
+	})
+
+	var (
+		base    = errors.New("user not found")
+		err     = errors.Wrap(errors.WithLocalized(base, "user.not_found", "alice"), "lookup")
+		unknown = errors.WithLocalized(base, "user.unknown")
+	)
+
+	require.Equal(t, "usuario alice no encontrado", errors.Localize(err, spanish))
+	require.Equal(t, "lookup: user not found", err.Error())
+	require.Equal(t, "lookup: user not found", errors.Localize(err, nil))
+	require.Equal(t, "user not found", errors.Localize(unknown, spanish))
+	require.Equal(t, "user not found", errors.Localize(base, spanish))
+	require.Equal(t, "", errors.Localize(nil, spanish))
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Logfmt renders err as a logfmt-formatted string of key-value pairs, e.g.:
+//
+//	msg="get user: not found" code=not_found field_user_id=42 cause="not found"
+//
+// The msg key contains err's message. The code key contains the name of
+// err's code (see [CodeOf]), and is omitted if err's code is unknown. Each of
+// err's fields (see [Fields]) is rendered with a "field_" key prefix, in key
+// order. The cause key contains the message of err's root cause, i.e. the
+// innermost error in its chain, and is omitted if it is identical to msg.
+//
+// If err is nil, Logfmt returns an empty string.
+func Logfmt(err error) string {
+	if LazyOrNil(err) == nil {
+		return ""
+	}
+
+	var (
+		b   strings.Builder
+		msg = err.Error()
+	)
+
+	writeLogfmtPair(&b, "msg", msg)
+	if code := CodeOf(err); code != CodeUnknown {
+		writeLogfmtPair(&b, "code", code.String())
+	}
+
+	fields := Fields(err)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeLogfmtPair(&b, "field_"+k, fmt.Sprint(fields[k]))
+	}
+
+	if cause := rootCause(err).Error(); cause != msg {
+		writeLogfmtPair(&b, "cause", cause)
+	}
+
+	return b.String()
+}
+
+func rootCause(err error) error {
+	guard := newChainGuard()
+	guard.visit(err)
+	for {
+		next := errors.Unwrap(err)
+		if next == nil || !guard.visit(next) {
+			return err
+		}
+		err = next
+	}
+}
+
+func writeLogfmtPair(b *strings.Builder, key string, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, key))
+	b.WriteByte('=')
+
+	if needsLogfmtQuote(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func needsLogfmtQuote(value string) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestLogfmt(t *testing.T) {
+	cases := map[string]struct {
+		give error
+		want string
+	}{
+		"nil": {
+			give: nil,
+			want: "",
+		},
+		"simple": {
+			give: errors.New("boom"),
+			want: "msg=boom",
+		},
+		"empty message": {
+			give: errors.New(""),
+			want: `msg=""`,
+		},
+		"nominal": {
+			give: errors.WithFields(
+				errors.Wrap(errors.NewCoded(errors.CodeNotFound, "not found"), "get user"),
+				map[string]any{
+					"user_id": 42,
+					"name":    "alice smith",
+				},
+			),
+			want: `msg="get user: not found" code=not_found ` +
+				`field_name="alice smith" field_user_id=42 cause="not found"`,
+		},
+		"escaping": {
+			give: errors.WithField(errors.New("a=\"b\"\nc"), "bad key", "x=y"),
+			want: `msg="a=\"b\"\nc" field_bad_key="x=y"`,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.Logfmt(tt.give))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package logruserr integrates errors with github.com/sirupsen/logrus.
+package logruserr
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.mway.dev/errors"
+)
+
+// Keys used for error metadata in logrus.Fields. Error fields (see
+// errors.WithField) are added using their own keys.
+const (
+	CodeKey  = "error_code"
+	StackKey = "error_stack"
+)
+
+// Fields returns err's metadata as logrus.Fields: its fields (see
+// errors.Fields), its code (see errors.CodeOf) if it carries a known code,
+// and the stack of a recovered panic in its chain (see errors.PanicError), if
+// any. If err is nil or has no metadata, Fields returns nil.
+func Fields(err error) logrus.Fields {
+	if err == nil {
+		return nil
+	}
+
+	var fields logrus.Fields
+	set := func(key string, value any) {
+		if fields == nil {
+			fields = make(logrus.Fields)
+		}
+		fields[key] = value
+	}
+
+	for k, v := range errors.Fields(err) {
+		set(k, v)
+	}
+	if code := errors.CodeOf(err); code != errors.CodeUnknown {
+		set(CodeKey, code.String())
+	}
+
+	var panicErr *errors.PanicError
+	if errors.As(err, &panicErr) {
+		set(StackKey, string(panicErr.Stack))
+	}
+
+	return fields
+}
+
+// A Hook is a logrus.Hook that enriches entries whose error field (see
+// logrus.ErrorKey) contains an error with metadata, as returned by Fields.
+// Existing entry data are never overwritten.
+type Hook struct{}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook returns a new Hook.
+func NewHook() *Hook {
+	return &Hook{}
+}
+
+// Levels returns all logrus levels.
+func (*Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire enriches entry with the metadata of its error, if any.
+func (*Hook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+
+	for k, v := range Fields(err) {
+		if _, exists := entry.Data[k]; !exists {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package logruserr_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/logruserr"
+)
+
+func TestFields(t *testing.T) {
+	err := errors.WithField(
+		errors.Wrap(errors.NewCoded(errors.CodeNotFound, "not found"), "get user"),
+		"user_id",
+		42,
+	)
+
+	require.Equal(t, logrus.Fields{
+		"user_id":    42,
+		"error_code": "not_found",
+	}, logruserr.Fields(err))
+
+	require.Nil(t, logruserr.Fields(nil))
+	require.Nil(t, logruserr.Fields(errors.New("foo")))
+}
+
+func TestFields_Panic(t *testing.T) {
+	err := func() (err error) {
+		defer func() {
+			err = errors.Recover(recover())
+		}()
+		panic("boom")
+	}()
+
+	fields := logruserr.Fields(err)
+	require.Contains(t, fields[logruserr.StackKey], "TestFields_Panic")
+}
+
+func TestHook(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		logger = logrus.New()
+	)
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	logger.AddHook(logruserr.NewHook())
+
+	err := errors.WithFields(
+		errors.NewCoded(errors.CodeUnavailable, "backend down"),
+		map[string]any{
+			"shard":   7,
+			"request": "original",
+		},
+	)
+
+	logger.WithError(err).WithField("request", "abc").Error("request failed")
+	require.Contains(t, buf.String(), `error="backend down"`)
+	require.Contains(t, buf.String(), "error_code=unavailable")
+	require.Contains(t, buf.String(), "shard=7")
+	require.Contains(t, buf.String(), "request=abc")
+	require.NotContains(t, buf.String(), "original")
+
+	buf.Reset()
+	logger.WithField("error", "not an error").Info("no error")
+	require.NotContains(t, buf.String(), "error_code")
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package neterr provides network error classification helpers, suitable for
+// making retry and failover decisions in network clients and proxies.
+package neterr
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+
+	"go.mway.dev/errors"
+)
+
+// IsDNSFailure reports whether err's chain contains a *net.DNSError.
+func IsDNSFailure(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// IsTLSHandshake reports whether err's chain contains an error produced while
+// negotiating a TLS connection, such as a certificate verification failure, a
+// received TLS alert, or a malformed TLS record.
+func IsTLSHandshake(err error) bool {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		alertErr     tls.AlertError
+		recordErr    tls.RecordHeaderError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &recordErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// Addr returns the remote address of the first *net.OpError in err's chain,
+// if it has one.
+func Addr(err error) (net.Addr, bool) {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Addr == nil {
+		return nil, false
+	}
+	return opErr.Addr, true
+}
+
+// Op returns the operation (e.g. "dial" or "read") of the first *net.OpError
+// in err's chain, if any.
+func Op(err error) (string, bool) {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return "", false
+	}
+	return opErr.Op, true
+}
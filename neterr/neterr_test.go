@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+//go:build !plan9
+
+package neterr_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/neterr"
+)
+
+func TestIsConnRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	_, err = net.Dial("tcp", addr)
+	err = errors.Wrap(err, "connect")
+
+	require.True(t, neterr.IsConnRefused(err))
+	require.False(t, neterr.IsConnReset(err))
+	require.False(t, neterr.IsDNSFailure(err))
+	require.False(t, neterr.IsTLSHandshake(err))
+
+	op, ok := neterr.Op(err)
+	require.True(t, ok)
+	require.Equal(t, "dial", op)
+
+	haveAddr, ok := neterr.Addr(err)
+	require.True(t, ok)
+	require.Equal(t, addr, haveAddr.String())
+}
+
+func TestIsConnReset(t *testing.T) {
+	err := errors.Wrap(&net.OpError{
+		Op:  "read",
+		Net: "tcp",
+		Err: os.NewSyscallError("read", syscall.ECONNRESET),
+	}, "read response")
+
+	require.True(t, neterr.IsConnReset(err))
+	require.False(t, neterr.IsConnRefused(err))
+
+	_, ok := neterr.Addr(err)
+	require.False(t, ok)
+}
+
+func TestIsDNSFailure(t *testing.T) {
+	var resolver net.Resolver
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := resolver.LookupHost(ctx, "example.invalid")
+	require.True(t, neterr.IsDNSFailure(errors.Wrap(err, "lookup")))
+	require.False(t, neterr.IsDNSFailure(errors.New("foo")))
+}
+
+func TestIsTLSHandshake(t *testing.T) {
+	cases := map[string]error{
+		"verification": &tls.CertificateVerificationError{
+			Err: x509.UnknownAuthorityError{},
+		},
+		"alert":     tls.AlertError(42),
+		"record":    tls.RecordHeaderError{Msg: "bad record"},
+		"authority": x509.UnknownAuthorityError{},
+		"hostname":  x509.HostnameError{Certificate: &x509.Certificate{}, Host: "foo"},
+		"invalid":   x509.CertificateInvalidError{Reason: x509.Expired},
+	}
+
+	for name, err := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, neterr.IsTLSHandshake(errors.Wrap(err, "handshake")))
+		})
+	}
+
+	require.False(t, neterr.IsTLSHandshake(nil))
+	require.False(t, neterr.IsTLSHandshake(errors.New("foo")))
+}
+
+func TestOp_NoOp(t *testing.T) {
+	op, ok := neterr.Op(errors.New("foo"))
+	require.False(t, ok)
+	require.Empty(t, op)
+}
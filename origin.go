@@ -0,0 +1,164 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Field keys used by [WithOrigin] to stamp an error's origin.
+const (
+	OriginHostKey    = "host"
+	OriginPIDKey     = "pid"
+	OriginServiceKey = "service"
+)
+
+// Field keys used by [WithBuildInfo] to stamp the build that produced an
+// error.
+const (
+	BuildVersionKey  = "version"
+	BuildRevisionKey = "revision"
+)
+
+// An Origin identifies the process in which errors occur, so that errors
+// serialized across process boundaries identify where they came from.
+type Origin struct {
+	// Hostname is the name of the host running the process.
+	Hostname string
+	// Service is the name of the service implemented by the process.
+	Service string
+	// PID is the process ID.
+	PID int
+}
+
+// NewOrigin returns an Origin for the current process, which implements the
+// given service. If the hostname cannot be determined, it is left empty.
+func NewOrigin(service string) Origin {
+	hostname, _ := os.Hostname() //nolint:errcheck
+	return Origin{
+		Hostname: hostname,
+		Service:  service,
+		PID:      os.Getpid(),
+	}
+}
+
+func (o Origin) fields() map[string]any {
+	fields := map[string]any{
+		OriginPIDKey: o.PID,
+	}
+	if len(o.Hostname) > 0 {
+		fields[OriginHostKey] = o.Hostname
+	}
+	if len(o.Service) > 0 {
+		fields[OriginServiceKey] = o.Service
+	}
+	return fields
+}
+
+// _origin holds the fields of the package-level Origin, which are shared by
+// all stamped errors and must not be modified.
+var _origin atomic.Pointer[map[string]any]
+
+// SetOrigin sets the package-level [Origin] used by [WithOrigin] and
+// [Report], and returns a function that restores the previous origin. If o is
+// nil, errors are not stamped. Stamping is opt-in: until an origin is set,
+// WithOrigin and Report leave errors unchanged.
+func SetOrigin(o *Origin) (restore func()) {
+	var ptr *map[string]any
+	if o != nil {
+		fields := o.fields()
+		ptr = &fields
+	}
+
+	prev := _origin.Swap(ptr)
+	return func() {
+		_origin.Store(prev)
+	}
+}
+
+// WithOrigin returns a new error that wraps err and carries the fields of the
+// package-level [Origin] (see [SetOrigin]), keyed by [OriginHostKey],
+// [OriginPIDKey], and [OriginServiceKey], which can be retrieved with
+// [Fields]. If no origin is set, err already carries an origin, or err is
+// nil, err is returned verbatim.
+//
+// If an origin is set, errors passed to [Report] are stamped automatically,
+// so WithOrigin is only needed to stamp errors that are serialized by other
+// means, e.g. in RPC responses.
+func WithOrigin(err error) error {
+	fields := _origin.Load()
+	if fields == nil || err == nil || hasOrigin(err) {
+		return err
+	}
+	return &fieldsError{
+		err:    err,
+		fields: *fields,
+	}
+}
+
+func hasOrigin(err error) bool {
+	return findInChain(err, func(err error) bool {
+		x, ok := err.(*fieldsError)
+		if !ok {
+			return false
+		}
+		_, ok = x.fields[OriginPIDKey]
+		return ok
+	}, newChainGuard())
+}
+
+var _buildFields = sync.OnceValue(func() map[string]any {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	fields := map[string]any{
+		BuildVersionKey: info.Main.Version,
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			fields[BuildRevisionKey] = setting.Value
+		}
+	}
+	return fields
+})
+
+// WithBuildInfo returns a new error that wraps err and carries the version of
+// the main module and the VCS revision of the running binary, keyed by
+// [BuildVersionKey] and [BuildRevisionKey], which can be retrieved with
+// [Fields], so that serialized errors identify exactly which build produced
+// them. The build information is read once, using runtime/debug.ReadBuildInfo;
+// the revision is omitted if the binary was not built with VCS information. If
+// build information is unavailable or err is nil, err is returned verbatim.
+func WithBuildInfo(err error) error {
+	fields := _buildFields()
+	if fields == nil || err == nil {
+		return err
+	}
+	return &fieldsError{
+		err:    err,
+		fields: fields,
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestNewOrigin(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	require.Equal(t, errors.Origin{
+		Hostname: hostname,
+		Service:  "svc",
+		PID:      os.Getpid(),
+	}, errors.NewOrigin("svc"))
+}
+
+func TestWithOrigin(t *testing.T) {
+	require.Equal(t, io.EOF, errors.WithOrigin(io.EOF)) // no origin set
+
+	restore := errors.SetOrigin(&errors.Origin{
+		Hostname: "host-1",
+		Service:  "svc",
+		PID:      42,
+	})
+	defer restore()
+
+	require.NoError(t, errors.WithOrigin(nil))
+
+	err := errors.WithOrigin(io.EOF)
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	want := map[string]any{
+		errors.OriginHostKey:    "host-1",
+		errors.OriginPIDKey:     42,
+		errors.OriginServiceKey: "svc",
+	}
+	require.Equal(t, want, errors.Fields(err))
+
+	// Errors that already carry an origin are not stamped again.
+	wrapped := errors.Wrap(err, "wrap")
+	require.Equal(t, wrapped, errors.WithOrigin(wrapped))
+
+	restoreOther := errors.SetOrigin(&errors.Origin{PID: 7})
+	require.Equal(t, map[string]any{
+		errors.OriginPIDKey: 7,
+	}, errors.Fields(errors.WithOrigin(io.EOF)))
+	restoreOther()
+
+	restoreNil := errors.SetOrigin(nil)
+	require.Equal(t, io.EOF, errors.WithOrigin(io.EOF))
+	restoreNil()
+
+	require.Equal(t, want, errors.Fields(errors.WithOrigin(io.EOF)))
+}
+
+func TestReport_Origin(t *testing.T) {
+	var reported []error
+	defer errors.SetReporter(func(_ context.Context, err error) {
+		reported = append(reported, err)
+	})()
+
+	errors.Report(context.Background(), io.EOF)
+
+	restore := errors.SetOrigin(&errors.Origin{Service: "svc", PID: 42})
+	errors.Report(context.Background(), io.EOF)
+	restore()
+
+	require.Len(t, reported, 2)
+	require.Equal(t, io.EOF, reported[0])
+	require.ErrorIs(t, reported[1], io.EOF)
+	require.Equal(t, map[string]any{
+		errors.OriginPIDKey:     42,
+		errors.OriginServiceKey: "svc",
+	}, errors.Fields(reported[1]))
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	require.NoError(t, errors.WithBuildInfo(nil))
+
+	info, ok := debug.ReadBuildInfo()
+	require.True(t, ok)
+
+	err := errors.WithBuildInfo(io.EOF)
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	fields := errors.Fields(err)
+	require.Equal(t, info.Main.Version, fields[errors.BuildVersionKey])
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			require.Equal(t, setting.Value, fields[errors.BuildRevisionKey])
+		}
+	}
+}
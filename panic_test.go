@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestRecover(t *testing.T) {
+	recovered := func(fn func()) (err error) {
+		defer func() {
+			err = errors.Recover(recover())
+		}()
+		fn()
+		return nil
+	}
+
+	err := recovered(func() { panic("boom") })
+	require.EqualError(t, err, "panic: boom")
+
+	var panicErr *errors.PanicError
+	require.True(t, errors.As(err, &panicErr))
+	require.Equal(t, "boom", panicErr.Value)
+	require.Contains(t, string(panicErr.Stack), "TestRecover")
+	require.Nil(t, errors.Unwrap(err))
+
+	require.Equal(t, "panic: boom", fmt.Sprintf("%v", err))
+	require.Equal(t, `"panic: boom"`, fmt.Sprintf("%q", err))
+	require.Equal(t, "panic: boom\n"+string(panicErr.Stack), fmt.Sprintf("%+v", err))
+
+	err = recovered(func() { panic(io.EOF) })
+	require.ErrorIs(t, err, io.EOF)
+
+	require.NoError(t, recovered(func() {}))
+}
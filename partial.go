@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+// A Partial holds the values successfully produced by an operation alongside
+// the errors for the parts of it that failed, e.g. the elements of a batch,
+// formalizing the contract of returning what succeeded in addition to what
+// did not. The zero value is an empty Partial ready for use. A Partial is not
+// safe for concurrent use.
+type Partial[T any] struct {
+	values []T
+	errs   []error
+}
+
+// Add records the result of producing a single value: if err is nil, value is
+// added to p's values; otherwise, err is added to p's errors and value is
+// discarded.
+func (p *Partial[T]) Add(value T, err error) {
+	if err == nil {
+		p.values = append(p.values, value)
+		return
+	}
+	p.errs = append(p.errs, err)
+}
+
+// Values returns the values added to p.
+func (p *Partial[T]) Values() []T {
+	return p.values
+}
+
+// Err returns an error joining the errors added to p (see [Join]), or nil if
+// no errors were added. If a single error was added, it is returned as-is.
+func (p *Partial[T]) Err() error {
+	switch len(p.errs) {
+	case 0:
+		return nil
+	case 1:
+		return p.errs[0]
+	default:
+		return Join(p.errs...)
+	}
+}
+
+// Failed returns the number of errors added to p.
+func (p *Partial[T]) Failed() int {
+	return len(p.errs)
+}
+
+// Complete returns whether p holds values only, i.e. no errors were added.
+func (p *Partial[T]) Complete() bool {
+	return len(p.errs) == 0
+}
+
+// Split returns p's values and error, as returned by [Partial.Values] and
+// [Partial.Err], respectively.
+func (p *Partial[T]) Split() ([]T, error) {
+	return p.values, p.Err()
+}
+
+// Merge adds the values and errors of each of others to p, in order. The
+// errors of others are added individually rather than as joined errors.
+func (p *Partial[T]) Merge(others ...*Partial[T]) {
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+		p.values = append(p.values, other.values...)
+		p.errs = append(p.errs, other.errs...)
+	}
+}
+
+// MergePartials returns a new Partial holding the values and errors of each
+// of partials, in order.
+func MergePartials[T any](partials ...*Partial[T]) *Partial[T] {
+	merged := &Partial[T]{}
+	merged.Merge(partials...)
+	return merged
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestPartial(t *testing.T) {
+	var p errors.Partial[int]
+	require.True(t, p.Complete())
+	require.Zero(t, p.Failed())
+	require.NoError(t, p.Err())
+	require.Empty(t, p.Values())
+
+	p.Add(1, nil)
+	p.Add(2, io.EOF)
+	p.Add(3, nil)
+	require.False(t, p.Complete())
+	require.Equal(t, 1, p.Failed())
+	require.Equal(t, []int{1, 3}, p.Values())
+	require.ErrorIs(t, p.Err(), io.EOF)
+	require.EqualError(t, p.Err(), "EOF")
+
+	p.Add(4, io.ErrUnexpectedEOF)
+	values, err := p.Split()
+	require.Equal(t, []int{1, 3}, values)
+	require.EqualError(t, err, "EOF\nunexpected EOF")
+	require.ErrorIs(t, err, io.EOF)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestMergePartials(t *testing.T) {
+	var a, b errors.Partial[string]
+	a.Add("a", nil)
+	a.Add("", io.EOF)
+	b.Add("b", nil)
+	b.Add("", io.ErrClosedPipe)
+
+	merged := errors.MergePartials(&a, nil, &b)
+	require.Equal(t, []string{"a", "b"}, merged.Values())
+	require.Equal(t, 2, merged.Failed())
+	require.EqualError(t, merged.Err(), "EOF\nio: read/write on closed pipe")
+
+	// Merging into a Partial does not modify the merged partials.
+	merged.Merge(&a)
+	require.Equal(t, []string{"a", "b", "a"}, merged.Values())
+	require.Equal(t, 3, merged.Failed())
+	require.Equal(t, 1, a.Failed())
+}
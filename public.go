@@ -0,0 +1,83 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"net/http"
+)
+
+// WithPublicMessage returns a new error that wraps err and carries a message
+// that is safe to show to end users, which can be retrieved with
+// [PublicMessage]. The returned error's message and chain are identical to
+// err's. If err is nil, WithPublicMessage returns nil.
+func WithPublicMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &publicError{
+		err: err,
+		msg: msg,
+	}
+}
+
+// PublicMessage returns the outermost public message in err's chain, as
+// attached by [WithPublicMessage], if any.
+func PublicMessage(err error) (string, bool) {
+	var public *publicError
+	if !As(err, &public) {
+		return "", false
+	}
+	return public.msg, true
+}
+
+// SafeMessage returns a message for err that is safe to show to end users, as
+// rendered by the transport adapters (httperr, grpcerr, rpcerr, and
+// graphqlerr). It is err's public message, if it has one (see
+// [PublicMessage]); otherwise, it is err's message for client errors (errors
+// whose [HTTPStatus] is 4xx), and the name of err's code (see [CodeOf]) for
+// all other errors, so that internal details are not exposed. If err is nil,
+// SafeMessage returns "".
+func SafeMessage(err error) string {
+	if LazyOrNil(err) == nil {
+		return ""
+	}
+	if msg, ok := PublicMessage(err); ok {
+		return msg
+	}
+	if status := HTTPStatus(err); status >= http.StatusBadRequest &&
+		status < http.StatusInternalServerError {
+		return err.Error()
+	}
+	return CodeOf(err).String()
+}
+
+type publicError struct {
+	err error
+	msg string
+}
+
+func (e *publicError) Unwrap() error {
+	return e.err
+}
+
+func (e *publicError) Error() string {
+	return e.err.Error()
+}
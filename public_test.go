@@ -0,0 +1,88 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithPublicMessage(t *testing.T) {
+	var (
+		base = errors.New("pq: connection refused")
+		err  = errors.WithPublicMessage(base, "service temporarily unavailable")
+	)
+
+	require.Equal(t, base.Error(), err.Error())
+	require.ErrorIs(t, err, base)
+	require.NoError(t, errors.WithPublicMessage(nil, "foo"))
+
+	msg, ok := errors.PublicMessage(errors.Wrap(err, "query"))
+	require.True(t, ok)
+	require.Equal(t, "service temporarily unavailable", msg)
+
+	outer := errors.WithPublicMessage(errors.Wrap(err, "query"), "try again later")
+	msg, ok = errors.PublicMessage(outer)
+	require.True(t, ok)
+	require.Equal(t, "try again later", msg)
+
+	_, ok = errors.PublicMessage(base)
+	require.False(t, ok)
+}
+
+func TestSafeMessage(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"nil": {
+			err:  nil,
+			want: "",
+		},
+		"public message": {
+			err: errors.WithPublicMessage(
+				errors.NewCoded(errors.CodeInternal, "db: connection refused"),
+				"try again later",
+			),
+			want: "try again later",
+		},
+		"client error": {
+			err:  errors.Wrap(errors.NewCoded(errors.CodeNotFound, "user 42"), "get user"),
+			want: "get user: user 42",
+		},
+		"server error": {
+			err:  errors.NewCoded(errors.CodeInternal, "db: connection refused"),
+			want: "internal",
+		},
+		"uncoded error": {
+			err:  errors.New("db: connection refused"),
+			want: "unknown",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.SafeMessage(tt.err))
+		})
+	}
+}
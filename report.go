@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// A Reporter reports errors to an external system, such as an error tracker
+// or a log pipeline.
+type Reporter = func(ctx context.Context, err error)
+
+var _reporter atomic.Pointer[Reporter]
+
+// SetReporter sets the package-level [Reporter] used by [Report], and returns
+// a function that restores the previous reporter. If r is nil, errors are not
+// reported.
+func SetReporter(r Reporter) (restore func()) {
+	var ptr *Reporter
+	if r != nil {
+		ptr = &r
+	}
+
+	prev := _reporter.Swap(ptr)
+	return func() {
+		_reporter.Store(prev)
+	}
+}
+
+// Report reports err using the package-level [Reporter], if one is set. If
+// the package-level [Origin] is set (see [SetOrigin]), err is stamped with it
+// before being reported (see [WithOrigin]). If err is nil, Report does
+// nothing.
+func Report(ctx context.Context, err error) {
+	if LazyOrNil(err) == nil {
+		return
+	}
+	if r := _reporter.Load(); r != nil {
+		(*r)(ctx, WithOrigin(err))
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+type reporterKey struct{}
+
+func TestReport(t *testing.T) {
+	var (
+		reported []error
+		ctx      = context.WithValue(context.Background(), reporterKey{}, "value")
+		errA     = errors.New("a")
+	)
+
+	errors.Report(ctx, errA) // no reporter; must not panic
+
+	restore := errors.SetReporter(func(ctx context.Context, err error) {
+		require.Equal(t, "value", ctx.Value(reporterKey{}))
+		reported = append(reported, err)
+	})
+	errors.Report(ctx, errA)
+	errors.Report(ctx, nil)
+	errors.Report(ctx, errors.Lazy(func() error { return nil }))
+	restore()
+	errors.Report(ctx, errA)
+
+	require.Equal(t, []error{errA}, reported)
+}
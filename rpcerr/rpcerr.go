@@ -0,0 +1,234 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package rpcerr converts errors to and from the wire representations used by
+// RPC frameworks such as connect-go and Twirp, preserving codes, registered
+// sentinels, and fields.
+//
+// This package does not depend on any RPC framework. Instead, it produces and
+// consumes the primitive parts of each framework's errors, which map directly
+// onto their constructors and accessors. For example, with connect-go:
+//
+//	code, msg, meta := rpcerr.ToConnect(err)
+//	cerr := connect.NewError(connect.Code(code), errors.New(msg))
+//	for k, v := range meta {
+//		cerr.Meta()[k] = v
+//	}
+//
+// and with Twirp:
+//
+//	code, msg, meta := rpcerr.ToTwirp(err)
+//	terr := twirp.NewError(twirp.ErrorCode(code), msg)
+//	for k, v := range meta {
+//		terr = terr.WithMeta(k, v)
+//	}
+package rpcerr
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.mway.dev/errors"
+)
+
+const (
+	// ConnectSentinelKey is the connect-go metadata key that carries the
+	// registered name of an error's sentinel (see errors.RegisterSentinel).
+	ConnectSentinelKey = "errors-sentinel"
+	// ConnectFieldPrefix is the prefix of connect-go metadata keys that carry
+	// error fields. Because connect-go metadata are HTTP headers, field keys
+	// are case-insensitive and are decoded in lowercase.
+	ConnectFieldPrefix = "errors-field-"
+	// TwirpSentinelKey is the Twirp metadata key that carries the registered
+	// name of an error's sentinel (see errors.RegisterSentinel).
+	TwirpSentinelKey = "sentinel"
+	// TwirpFieldPrefix is the prefix of Twirp metadata keys that carry error
+	// fields.
+	TwirpFieldPrefix = "field."
+)
+
+var _twirpCodes = map[errors.Code]string{
+	errors.CodeOK:                 "",
+	errors.CodeCanceled:           "canceled",
+	errors.CodeUnknown:            "unknown",
+	errors.CodeInvalidArgument:    "invalid_argument",
+	errors.CodeDeadlineExceeded:   "deadline_exceeded",
+	errors.CodeNotFound:           "not_found",
+	errors.CodeAlreadyExists:      "already_exists",
+	errors.CodePermissionDenied:   "permission_denied",
+	errors.CodeResourceExhausted:  "resource_exhausted",
+	errors.CodeFailedPrecondition: "failed_precondition",
+	errors.CodeAborted:            "aborted",
+	errors.CodeOutOfRange:         "out_of_range",
+	errors.CodeUnimplemented:      "unimplemented",
+	errors.CodeInternal:           "internal",
+	errors.CodeUnavailable:        "unavailable",
+	errors.CodeDataLoss:           "dataloss",
+	errors.CodeUnauthenticated:    "unauthenticated",
+}
+
+var _twirpCodesReverse = func() map[string]errors.Code {
+	reverse := make(map[string]errors.Code, len(_twirpCodes)+2)
+	for code, str := range _twirpCodes {
+		reverse[str] = code
+	}
+	reverse["malformed"] = errors.CodeInvalidArgument
+	reverse["bad_route"] = errors.CodeUnimplemented
+	return reverse
+}()
+
+// TwirpCode returns the Twirp error code that corresponds to code. Unknown
+// codes map to "unknown".
+func TwirpCode(code errors.Code) string {
+	if str, ok := _twirpCodes[code]; ok {
+		return str
+	}
+	return _twirpCodes[errors.CodeUnknown]
+}
+
+// CodeFromTwirp returns the errors.Code that corresponds to the given Twirp
+// error code. Unknown Twirp codes map to errors.CodeUnknown.
+func CodeFromTwirp(code string) errors.Code {
+	if c, ok := _twirpCodesReverse[code]; ok {
+		return c
+	}
+	return errors.CodeUnknown
+}
+
+// ToTwirp returns the parts of a Twirp error that represent err: its code,
+// message, and metadata. The message is derived with errors.SafeMessage.
+// Metadata include the
+// registered name of err's sentinel, if any, and err's fields, formatted with
+// fmt.Sprint.
+func ToTwirp(err error) (code string, msg string, meta map[string]string) {
+	if err == nil {
+		return TwirpCode(errors.CodeOK), "", nil
+	}
+
+	meta = make(map[string]string)
+	if name, ok := errors.SentinelName(err); ok {
+		meta[TwirpSentinelKey] = name
+	}
+	for k, v := range errors.Fields(err) {
+		meta[TwirpFieldPrefix+k] = fmt.Sprint(v)
+	}
+
+	c := errors.CodeOf(err)
+	return TwirpCode(c), errors.SafeMessage(err), meta
+}
+
+// FromTwirp returns an error reconstructed from the parts of a Twirp error.
+// If meta names a registered sentinel, the returned error wraps it and thus
+// matches it with errors.Is; otherwise, the returned error carries the
+// corresponding errors.Code. Fields in meta are restored as string values.
+func FromTwirp(code string, msg string, meta map[string]string) error {
+	fields := make(map[string]any)
+	for k, v := range meta {
+		if key, ok := strings.CutPrefix(k, TwirpFieldPrefix); ok {
+			fields[key] = v
+		}
+	}
+	return decode(CodeFromTwirp(code), msg, meta[TwirpSentinelKey], fields)
+}
+
+// ToConnect returns the parts of a connect-go error that represent err: its
+// code, message, and metadata. Because connect-go codes are numerically
+// identical to errors.Code values, the returned code can be converted directly
+// with connect.Code(code). The message is chosen as by [ToTwirp]. Metadata
+// include the registered name of err's sentinel, if any, and err's fields,
+// formatted with fmt.Sprint.
+func ToConnect(err error) (code uint32, msg string, meta http.Header) {
+	if err == nil {
+		return uint32(errors.CodeOK), "", nil
+	}
+
+	meta = make(http.Header)
+	if name, ok := errors.SentinelName(err); ok {
+		meta.Set(ConnectSentinelKey, name)
+	}
+	for k, v := range errors.Fields(err) {
+		meta.Set(ConnectFieldPrefix+k, fmt.Sprint(v))
+	}
+
+	c := errors.CodeOf(err)
+	return uint32(c), errors.SafeMessage(err), meta
+}
+
+// FromConnect returns an error reconstructed from the parts of a connect-go
+// error. If meta names a registered sentinel, the returned error wraps it and
+// thus matches it with errors.Is; otherwise, the returned error carries the
+// corresponding errors.Code. Fields in meta are restored as string values,
+// with lowercase keys.
+func FromConnect(code uint32, msg string, meta http.Header) error {
+	fields := make(map[string]any)
+	for k, v := range meta {
+		key, ok := cutPrefixFold(k, ConnectFieldPrefix)
+		if ok && len(v) > 0 {
+			fields[strings.ToLower(key)] = v[0]
+		}
+	}
+	return decode(errors.Code(code), msg, meta.Get(ConnectSentinelKey), fields)
+}
+
+func decode(code errors.Code, msg string, sentinel string, fields map[string]any) error {
+	if code == errors.CodeOK {
+		return nil
+	}
+
+	var err error
+	if base, ok := errors.LookupSentinel(sentinel); ok {
+		err = &remoteError{
+			msg:  msg,
+			code: code,
+			base: base,
+		}
+	} else {
+		err = errors.NewCoded(code, msg)
+	}
+
+	return errors.WithFields(err, fields)
+}
+
+func cutPrefixFold(s string, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// remoteError is a decoded error that matches a local sentinel.
+type remoteError struct {
+	base error
+	msg  string
+	code errors.Code
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.base
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Code() errors.Code {
+	return e.code
+}
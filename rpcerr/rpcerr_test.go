@@ -0,0 +1,154 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package rpcerr_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/rpcerr"
+)
+
+var errUserNotFound = errors.NewCoded(errors.CodeNotFound, "user not found")
+
+func init() {
+	errors.RegisterSentinel("rpcerr_test.ErrUserNotFound", errUserNotFound)
+}
+
+func TestTwirpCode(t *testing.T) {
+	for code := errors.CodeOK; code <= errors.CodeUnauthenticated; code++ {
+		require.Equal(t, code, rpcerr.CodeFromTwirp(rpcerr.TwirpCode(code)))
+	}
+
+	require.Equal(t, "not_found", rpcerr.TwirpCode(errors.CodeNotFound))
+	require.Equal(t, "dataloss", rpcerr.TwirpCode(errors.CodeDataLoss))
+	require.Equal(t, "unknown", rpcerr.TwirpCode(errors.Code(1234)))
+	require.Equal(t, errors.CodeInvalidArgument, rpcerr.CodeFromTwirp("malformed"))
+	require.Equal(t, errors.CodeUnimplemented, rpcerr.CodeFromTwirp("bad_route"))
+	require.Equal(t, errors.CodeUnknown, rpcerr.CodeFromTwirp("bogus"))
+}
+
+func TestTwirp_RoundTrip(t *testing.T) {
+	err := errors.WithField(errors.Wrap(errUserNotFound, "get user"), "user_id", 42)
+
+	code, msg, meta := rpcerr.ToTwirp(err)
+	require.Equal(t, "not_found", code)
+	require.Equal(t, "get user: user not found", msg)
+	require.Equal(t, map[string]string{
+		"sentinel":      "rpcerr_test.ErrUserNotFound",
+		"field.user_id": "42",
+	}, meta)
+
+	decoded := rpcerr.FromTwirp(code, msg, meta)
+	require.ErrorIs(t, decoded, errUserNotFound)
+	require.Equal(t, msg, decoded.Error())
+	require.Equal(t, errors.CodeNotFound, errors.CodeOf(decoded))
+	require.Equal(t, map[string]any{"user_id": "42"}, errors.Fields(decoded))
+}
+
+func TestTwirp_Unregistered(t *testing.T) {
+	code, msg, meta := rpcerr.ToTwirp(errors.NewCoded(errors.CodeUnavailable, "down"))
+	require.Equal(t, "unavailable", code)
+	require.Empty(t, meta)
+
+	require.Equal(t, "unavailable", msg)
+
+	decoded := rpcerr.FromTwirp(code, msg, meta)
+	require.Equal(t, "unavailable", decoded.Error())
+	require.Equal(t, errors.CodeUnavailable, errors.CodeOf(decoded))
+	require.Nil(t, errors.Fields(decoded))
+}
+
+func TestMessage(t *testing.T) {
+	internal := errors.Wrap(errors.NewCoded(errors.CodeInternal, "dial 10.0.0.1"), "query")
+	cases := map[string]struct {
+		give error
+		want string
+	}{
+		"client error": {
+			give: errors.NewCoded(errors.CodeInvalidArgument, "bad id"),
+			want: "bad id",
+		},
+		"server error": {
+			give: internal,
+			want: "internal",
+		},
+		"public message": {
+			give: errors.WithPublicMessage(internal, "try again later"),
+			want: "try again later",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, msg, _ := rpcerr.ToTwirp(tt.give)
+			require.Equal(t, tt.want, msg)
+
+			_, msg, _ = rpcerr.ToConnect(tt.give)
+			require.Equal(t, tt.want, msg)
+		})
+	}
+}
+
+func TestTwirp_Nil(t *testing.T) {
+	code, msg, meta := rpcerr.ToTwirp(nil)
+	require.Empty(t, code)
+	require.Empty(t, msg)
+	require.Nil(t, meta)
+	require.NoError(t, rpcerr.FromTwirp(code, msg, meta))
+}
+
+func TestConnect_RoundTrip(t *testing.T) {
+	err := errors.WithField(errors.Wrap(errUserNotFound, "get user"), "user_id", 42)
+
+	code, msg, meta := rpcerr.ToConnect(err)
+	require.Equal(t, uint32(errors.CodeNotFound), code)
+	require.Equal(t, "get user: user not found", msg)
+	require.Equal(t, "rpcerr_test.ErrUserNotFound", meta.Get(rpcerr.ConnectSentinelKey))
+	require.Equal(t, "42", meta.Get(rpcerr.ConnectFieldPrefix+"user_id"))
+
+	decoded := rpcerr.FromConnect(code, msg, meta)
+	require.ErrorIs(t, decoded, errUserNotFound)
+	require.Equal(t, msg, decoded.Error())
+	require.Equal(t, errors.CodeNotFound, errors.CodeOf(decoded))
+	require.Equal(t, map[string]any{"user_id": "42"}, errors.Fields(decoded))
+}
+
+func TestConnect_Unregistered(t *testing.T) {
+	meta := http.Header{}
+	meta.Set("Errors-Field-Attempt", "3")
+	meta.Set("Unrelated", "foo")
+
+	decoded := rpcerr.FromConnect(uint32(errors.CodeAborted), "aborted", meta)
+	require.Equal(t, "aborted", decoded.Error())
+	require.Equal(t, errors.CodeAborted, errors.CodeOf(decoded))
+	require.Equal(t, map[string]any{"attempt": "3"}, errors.Fields(decoded))
+}
+
+func TestConnect_Nil(t *testing.T) {
+	code, msg, meta := rpcerr.ToConnect(nil)
+	require.Zero(t, code)
+	require.Empty(t, msg)
+	require.Nil(t, meta)
+	require.NoError(t, rpcerr.FromConnect(code, msg, meta))
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"sync"
+)
+
+var _sentinels sentinelRegistry
+
+// A Sentinel is a string-based error, intended to be used to declare constant
+// sentinel errors:
+//
+//	const ErrNotFound = errors.Sentinel("not found")
+//
+// Unlike errors created with [New], Sentinel errors can be declared as
+// constants, and thus cannot be reassigned and do not require any allocation
+// at initialization time. Two Sentinel errors with the same text are equal.
+type Sentinel string
+
+// Error returns the sentinel's message.
+func (s Sentinel) Error() string {
+	return string(s)
+}
+
+// RegisterSentinel registers err under the given name, which should be stable
+// and namespaced (e.g. "storage.ErrNotFound"). Registered sentinels can be
+// retrieved by name with [LookupSentinel], and the name of a registered
+// sentinel can be derived from any error that contains it with
+// [SentinelName].
+//
+// RegisterSentinel is intended to be called during initialization, and panics
+// if name is empty, if err is nil, or if name is already registered.
+func RegisterSentinel(name string, err error) {
+	_sentinels.register(name, err)
+}
+
+// LookupSentinel returns the sentinel error registered with the given name, if
+// any.
+func LookupSentinel(name string) (error, bool) { //nolint:revive
+	return _sentinels.lookup(name)
+}
+
+// SentinelName returns the registered name of the first registered sentinel,
+// in registration order, that is present in err's chain according to [Is].
+func SentinelName(err error) (string, bool) {
+	return _sentinels.name(err)
+}
+
+// Sentinels returns a copy of all registered sentinels, keyed by name.
+func Sentinels() map[string]error {
+	return _sentinels.all()
+}
+
+type sentinelRegistry struct {
+	byName map[string]error
+	names  []string
+	mu     sync.RWMutex
+}
+
+func (r *sentinelRegistry) register(name string, err error) {
+	switch {
+	case len(name) == 0:
+		panic("errors: RegisterSentinel name is empty")
+	case err == nil:
+		panic("errors: RegisterSentinel error is nil for " + name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, dup := r.byName[name]; dup {
+		panic("errors: RegisterSentinel called twice for " + name)
+	}
+	if r.byName == nil {
+		r.byName = make(map[string]error)
+	}
+
+	r.byName[name] = err
+	r.names = append(r.names, name)
+}
+
+func (r *sentinelRegistry) lookup(name string) (error, bool) { //nolint:revive
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	err, ok := r.byName[name]
+	return err, ok
+}
+
+func (r *sentinelRegistry) name(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, name := range r.names {
+		if Is(err, r.byName[name]) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (r *sentinelRegistry) all() map[string]error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make(map[string]error, len(r.byName))
+	for name, err := range r.byName {
+		all[name] = err
+	}
+	return all
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+const errTestSentinel = errors.Sentinel("test sentinel")
+
+var (
+	errRegisteredFoo = errors.New("foo")
+	errRegisteredBar = errors.Sentinel("bar")
+)
+
+func init() {
+	errors.RegisterSentinel("errors_test.ErrFoo", errRegisteredFoo)
+	errors.RegisterSentinel("errors_test.ErrBar", errRegisteredBar)
+	errors.RegisterSentinel("errors_test.ErrDuplicate", errors.New("dup"))
+}
+
+func TestSentinel(t *testing.T) {
+	require.Equal(t, "test sentinel", errTestSentinel.Error())
+
+	var err error = errTestSentinel
+	require.ErrorIs(t, err, errTestSentinel)
+	require.ErrorIs(t, errors.Wrap(err, "wrapped"), errTestSentinel)
+	require.NotErrorIs(t, err, errors.Sentinel("other"))
+	require.ErrorIs(t, err, errors.Sentinel("test sentinel"))
+
+	var dst errors.Sentinel
+	require.True(t, errors.As(errors.Wrap(err, "wrapped"), &dst))
+	require.Equal(t, errTestSentinel, dst)
+}
+
+func TestRegisterSentinel(t *testing.T) {
+	var (
+		errFoo = errRegisteredFoo
+		errBar = errRegisteredBar
+	)
+
+	haveErr, ok := errors.LookupSentinel("errors_test.ErrFoo")
+	require.True(t, ok)
+	require.Equal(t, errFoo, haveErr)
+
+	haveErr, ok = errors.LookupSentinel("errors_test.ErrBar")
+	require.True(t, ok)
+	require.Equal(t, errBar, haveErr)
+
+	_, ok = errors.LookupSentinel("errors_test.ErrBaz")
+	require.False(t, ok)
+
+	name, ok := errors.SentinelName(errors.Wrap(errBar, "wrapped"))
+	require.True(t, ok)
+	require.Equal(t, "errors_test.ErrBar", name)
+
+	_, ok = errors.SentinelName(errors.New("foo"))
+	require.False(t, ok)
+	_, ok = errors.SentinelName(nil)
+	require.False(t, ok)
+
+	all := errors.Sentinels()
+	require.Equal(t, errFoo, all["errors_test.ErrFoo"])
+	require.Equal(t, errBar, all["errors_test.ErrBar"])
+}
+
+func TestRegisterSentinel_Panics(t *testing.T) {
+	require.Panics(t, func() {
+		errors.RegisterSentinel("", errors.New("foo"))
+	})
+	require.Panics(t, func() {
+		errors.RegisterSentinel("errors_test.ErrNil", nil)
+	})
+	require.Panics(t, func() {
+		errors.RegisterSentinel("errors_test.ErrDuplicate", errors.New("dup"))
+	})
+}
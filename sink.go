@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"sync"
+)
+
+// A Sink collects errors sent to it, e.g. by a library that reports
+// asynchronous failures, and exposes them as a single joined error. APIs that
+// would otherwise accept a chan error or a func(error) callback can accept a
+// *Sink instead; its Send method can be used wherever a func(error) is
+// expected. The zero value is an unbounded Sink without deduplication ready
+// for use. A Sink is safe for concurrent use.
+type Sink struct {
+	seen     map[string]struct{}
+	errs     []error
+	capacity int
+	dropped  int
+	mu       sync.Mutex
+	dedup    bool
+}
+
+// NewSink returns a new Sink that retains at most capacity errors, dropping
+// any errors sent after it is full. If capacity is not positive, the Sink is
+// unbounded. If dedup is true, errors whose messages are identical to that of
+// an error already retained by the Sink are dropped.
+func NewSink(capacity int, dedup bool) *Sink {
+	return &Sink{
+		capacity: capacity,
+		dedup:    dedup,
+	}
+}
+
+// Send adds err to s. Nil errors are ignored.
+func (s *Sink) Send(err error) {
+	if err == nil {
+		return
+	}
+
+	var msg string
+	if s.dedup {
+		msg = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.capacity > 0 && len(s.errs) >= s.capacity {
+		s.dropped++
+		return
+	}
+
+	if s.dedup {
+		if _, ok := s.seen[msg]; ok {
+			s.dropped++
+			return
+		}
+		if s.seen == nil {
+			s.seen = make(map[string]struct{})
+		}
+		s.seen[msg] = struct{}{}
+	}
+
+	s.errs = append(s.errs, err)
+}
+
+// Err returns an error joining the errors retained by s in the order in which
+// they were sent (see [Join]), or nil if no errors have been retained. If only
+// one error has been retained, it is returned verbatim.
+func (s *Sink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch len(s.errs) {
+	case 0:
+		return nil
+	case 1:
+		return s.errs[0]
+	default:
+		return Join(s.errs...)
+	}
+}
+
+// Dropped returns the number of errors that were sent to s but not retained,
+// either because s was full or because they were duplicates.
+func (s *Sink) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestSink(t *testing.T) {
+	cases := map[string]struct {
+		wantErr     string
+		give        []error
+		capacity    int
+		wantDropped int
+		dedup       bool
+	}{
+		"empty": {},
+		"nils": {
+			give: []error{nil, nil},
+		},
+		"single": {
+			give:    []error{io.EOF},
+			wantErr: "EOF",
+		},
+		"unbounded": {
+			give:    []error{io.EOF, io.EOF, io.ErrUnexpectedEOF},
+			wantErr: "EOF\nEOF\nunexpected EOF",
+		},
+		"capacity": {
+			give:        []error{io.EOF, io.ErrUnexpectedEOF, io.ErrClosedPipe},
+			capacity:    2,
+			wantErr:     "EOF\nunexpected EOF",
+			wantDropped: 1,
+		},
+		"dedup": {
+			give:        []error{io.EOF, errors.New("EOF"), io.ErrUnexpectedEOF},
+			dedup:       true,
+			wantErr:     "EOF\nunexpected EOF",
+			wantDropped: 1,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			sink := errors.NewSink(tt.capacity, tt.dedup)
+			for _, err := range tt.give {
+				sink.Send(err)
+			}
+
+			require.Equal(t, tt.wantDropped, sink.Dropped())
+			if tt.wantErr == "" {
+				require.NoError(t, sink.Err())
+				return
+			}
+			require.EqualError(t, sink.Err(), tt.wantErr)
+		})
+	}
+}
+
+func TestSink_Concurrent(t *testing.T) {
+	var (
+		sink errors.Sink
+		send func(error) = sink.Send
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			send(fmt.Errorf("error %d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	err := sink.Err()
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 10)
+	require.Zero(t, sink.Dropped())
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package slogerr integrates errors with log/slog.
+package slogerr
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"go.mway.dev/errors"
+)
+
+// Keys used within expanded error groups.
+const (
+	MessageKey = "message"
+	ChainKey   = "chain"
+	CodeKey    = "code"
+	FieldsKey  = "fields"
+	StackKey   = "stack"
+)
+
+// A Handler is a slog.Handler that expands error attributes into structured
+// groups before delegating to another handler. For example, the attribute
+// slog.Any("err", err) is expanded into an "err" group containing:
+//
+//   - message: the error's message;
+//   - chain: the messages of each error in the error's chain, if the error
+//     wraps other errors;
+//   - code: the error's code (see errors.CodeOf), if known;
+//   - fields: a group of the error's fields (see errors.Fields), if any;
+//   - stack: the stack of a recovered panic (see errors.PanicError), if any.
+//
+// Because expansion happens within the handler, no changes are required at
+// logging call sites.
+type Handler struct {
+	next slog.Handler
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a new Handler that delegates to next.
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{
+		next: next,
+	}
+}
+
+// Enabled reports whether the underlying handler handles records at the
+// given level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands error attributes in r and delegates to the underlying
+// handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(Expand(attr))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+// WithAttrs returns a new Handler whose underlying handler has the given
+// attributes, with any error attributes expanded.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = Expand(attr)
+	}
+	return NewHandler(h.next.WithAttrs(expanded))
+}
+
+// WithGroup returns a new Handler whose underlying handler has the given
+// group.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return NewHandler(h.next.WithGroup(name))
+}
+
+// Expand expands attr into a structured group if its value is an error, as
+// described by Handler. Groups are expanded recursively; other attributes are
+// returned with their values resolved.
+func Expand(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]any, len(group))
+		for i, a := range group {
+			expanded[i] = Expand(a)
+		}
+		return slog.Group(attr.Key, expanded...)
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok && err != nil {
+			return slog.Group(attr.Key, errorAttrs(err)...)
+		}
+	}
+
+	return attr
+}
+
+func errorAttrs(err error) []any {
+	attrs := []any{
+		slog.String(MessageKey, err.Error()),
+	}
+
+	if chain := messageChain(err); len(chain) > 1 {
+		attrs = append(attrs, slog.Any(ChainKey, chain))
+	}
+	if code := errors.CodeOf(err); code != errors.CodeUnknown {
+		attrs = append(attrs, slog.String(CodeKey, code.String()))
+	}
+	if fields := errors.Fields(err); len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fieldAttrs := make([]any, len(keys))
+		for i, k := range keys {
+			fieldAttrs[i] = slog.Any(k, fields[k])
+		}
+		attrs = append(attrs, slog.Group(FieldsKey, fieldAttrs...))
+	}
+
+	var panicErr *errors.PanicError
+	if errors.As(err, &panicErr) {
+		attrs = append(attrs, slog.String(StackKey, string(panicErr.Stack)))
+	}
+
+	return attrs
+}
+
+// messageChain returns the distinct messages of the errors in err's chain, up
+// to and including the first joined error.
+func messageChain(err error) []string {
+	var chain []string
+	errors.Walk(err, func(err error) bool {
+		msg := err.Error()
+		if len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+		_, joined := err.(interface{ Unwrap() []error })
+		return !joined
+	})
+	return chain
+}
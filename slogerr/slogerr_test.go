@@ -0,0 +1,167 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package slogerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/slogerr"
+)
+
+func TestHandler(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		logger = slog.New(slogerr.NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: dropTime,
+		})))
+		err = errors.WithField(
+			errors.Wrap(errors.NewCoded(errors.CodeNotFound, "not found"), "get user"),
+			"user_id",
+			42,
+		)
+	)
+
+	logger.Error("request failed", "err", err, "attempt", 1)
+	require.JSONEq(t, `{
+		"level": "ERROR",
+		"msg": "request failed",
+		"err": {
+			"message": "get user: not found",
+			"chain": ["get user: not found", "not found"],
+			"code": "not_found",
+			"fields": {"user_id": 42}
+		},
+		"attempt": 1
+	}`, buf.String())
+}
+
+func TestHandler_Cycles(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		logger = slog.New(slogerr.NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: dropTime,
+		})))
+		self = &cyclicJoinError{}
+	)
+	self.errs = []error{self, errors.WithField(self, "user_id", 42), self}
+
+	logger.Error("request failed", "err", errors.Wrap(self, "get user"))
+	require.JSONEq(t, `{
+		"level": "ERROR",
+		"msg": "request failed",
+		"err": {
+			"message": "get user: cyclic join",
+			"chain": ["get user: cyclic join", "cyclic join"],
+			"fields": {"user_id": 42}
+		}
+	}`, buf.String())
+}
+
+// cyclicJoinError is a joined error that may contain itself.
+type cyclicJoinError struct {
+	errs []error
+}
+
+func (e *cyclicJoinError) Error() string {
+	return "cyclic join"
+}
+
+func (e *cyclicJoinError) Unwrap() []error {
+	return e.errs
+}
+
+func TestHandler_WithAttrsAndGroups(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		logger = slog.New(slogerr.NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: dropTime,
+		})))
+	)
+
+	logger.
+		With("cause", errors.New("boom")).
+		WithGroup("req").
+		Info("handled", slog.Group("inner", "err", errors.New("nested")))
+
+	require.JSONEq(t, `{
+		"level": "INFO",
+		"msg": "handled",
+		"cause": {"message": "boom"},
+		"req": {"inner": {"err": {"message": "nested"}}}
+	}`, buf.String())
+}
+
+func TestHandler_Lazy(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		logger = slog.New(slogerr.NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: dropTime,
+		})))
+	)
+
+	logger.Info("lazy", "err", errors.Lazy(func() error {
+		return errors.NewCoded(errors.CodeAborted, "aborted")
+	}))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, map[string]any{
+		"message": "aborted",
+		"code":    "aborted",
+	}, record["err"])
+}
+
+func TestExpand_Panic(t *testing.T) {
+	err := func() (err error) {
+		defer func() {
+			err = errors.Recover(recover())
+		}()
+		panic("boom")
+	}()
+
+	attr := slogerr.Expand(slog.Any("err", err))
+	require.Equal(t, slog.KindGroup, attr.Value.Kind())
+
+	var stack string
+	for _, a := range attr.Value.Group() {
+		if a.Key == slogerr.StackKey {
+			stack = a.Value.String()
+		}
+	}
+	require.Contains(t, stack, "TestExpand_Panic")
+}
+
+func TestExpand_NonError(t *testing.T) {
+	attr := slogerr.Expand(slog.Int("n", 1))
+	require.Equal(t, slog.Int("n", 1), attr)
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package sqlerr
+
+import (
+	"reflect"
+
+	"go.mway.dev/errors"
+)
+
+// mysqlAdapter classifies *mysql.MySQLError values from
+// github.com/go-sql-driver/mysql. Because this package does not depend on any
+// driver, the error is identified structurally: a struct named MySQLError
+// with a uint16 Number field.
+func mysqlAdapter(err error) (Kind, bool) {
+	number, ok := mysqlErrorNumber(err)
+	if !ok {
+		return Unknown, false
+	}
+
+	switch number {
+	case 1213: // ER_LOCK_DEADLOCK
+		return Deadlock, true
+	case 1062, 1586: // ER_DUP_ENTRY, ER_DUP_ENTRY_WITH_KEY_NAME
+		return UniqueViolation, true
+	case 1451, 1452: // ER_ROW_IS_REFERENCED_2, ER_NO_REFERENCED_ROW_2
+		return ForeignKeyViolation, true
+	case 1048: // ER_BAD_NULL_ERROR
+		return NotNullViolation, true
+	case 3819: // ER_CHECK_CONSTRAINT_VIOLATED
+		return CheckViolation, true
+	default:
+		return Unknown, false
+	}
+}
+
+// mysqlErrorNumber returns the Number of the first MySQLError in err's chain,
+// if any.
+func mysqlErrorNumber(err error) (number uint16, ok bool) {
+	errors.Walk(err, func(err error) bool {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() == reflect.Struct && v.Type().Name() == "MySQLError" {
+			if field := v.FieldByName("Number"); field.Kind() == reflect.Uint16 {
+				number, ok = uint16(field.Uint()), true
+				return false
+			}
+		}
+		return true
+	})
+	return number, ok
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package sqlerr_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/sqlerr"
+)
+
+func TestCategory_MySQL(t *testing.T) {
+	cases := map[uint16]sqlerr.Kind{
+		1213: sqlerr.Deadlock,
+		1062: sqlerr.UniqueViolation,
+		1586: sqlerr.UniqueViolation,
+		1451: sqlerr.ForeignKeyViolation,
+		1452: sqlerr.ForeignKeyViolation,
+		1048: sqlerr.NotNullViolation,
+		3819: sqlerr.CheckViolation,
+		1064: sqlerr.Unknown,
+	}
+
+	for number, want := range cases {
+		t.Run(strconv.Itoa(int(number)), func(t *testing.T) {
+			err := errors.Wrap(&MySQLError{Number: number}, "exec")
+			require.Equal(t, want, sqlerr.Category(err))
+
+			joined := errors.Join(errors.New("foo"), &MySQLError{Number: number})
+			require.Equal(t, want, sqlerr.Category(joined))
+		})
+	}
+}
+
+func TestCategory_MySQLCycles(t *testing.T) {
+	self := &cyclicJoinError{}
+	self.errs = []error{self, self, &MySQLError{Number: 1213}}
+	require.Equal(t, sqlerr.Deadlock, sqlerr.Category(errors.Wrap(self, "exec")))
+
+	// Without shared cycle state, each level of this DAG would triple the
+	// number of errors visited.
+	var dag error = errors.New("foo")
+	for i := 0; i < 64; i++ {
+		dag = &cyclicJoinError{errs: []error{dag, dag, dag}}
+	}
+	require.Equal(t, sqlerr.Unknown, sqlerr.Category(dag))
+}
+
+// MySQLError mirrors github.com/go-sql-driver/mysql.MySQLError.
+type MySQLError struct {
+	Message  string
+	Number   uint16
+	SQLState [5]byte
+}
+
+func (e *MySQLError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+// cyclicJoinError is a joined error that may contain itself.
+type cyclicJoinError struct {
+	errs []error
+}
+
+func (e *cyclicJoinError) Error() string {
+	return "cyclic join"
+}
+
+func (e *cyclicJoinError) Unwrap() []error {
+	return e.errs
+}
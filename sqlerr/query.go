@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package sqlerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxQueryLen is the maximum length of a query attached by WrapQuery; longer
+// queries are truncated.
+const MaxQueryLen = 256
+
+// A QueryError is an error that occurred while executing a query.
+type QueryError struct {
+	// Err is the underlying error.
+	Err error
+	// Query is the sanitized query: whitespace is collapsed, and the query is
+	// truncated to MaxQueryLen bytes.
+	Query string
+	// ArgTypes are the types of the query's arguments. Argument values are
+	// intentionally omitted, as they may contain sensitive data.
+	ArgTypes []string
+}
+
+// WrapQuery returns a new *QueryError that wraps err with sanitized context
+// about the query that produced it. If err is nil, WrapQuery returns nil.
+func WrapQuery(err error, query string, args []any) error {
+	if err == nil {
+		return nil
+	}
+
+	var argTypes []string
+	if len(args) > 0 {
+		argTypes = make([]string, len(args))
+		for i, arg := range args {
+			argTypes[i] = fmt.Sprintf("%T", arg)
+		}
+	}
+
+	return &QueryError{
+		Err:      err,
+		Query:    sanitizeQuery(query),
+		ArgTypes: argTypes,
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns the error message, including the sanitized query.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query %q: %v", e.Query, e.Err)
+}
+
+func sanitizeQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > MaxQueryLen {
+		query = strings.ToValidUTF8(query[:MaxQueryLen], "") + "..."
+	}
+	return query
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package sqlerr_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/sqlerr"
+)
+
+func TestWrapQuery(t *testing.T) {
+	err := sqlerr.WrapQuery(
+		sql.ErrNoRows,
+		"SELECT *\n\tFROM users\n\tWHERE id = $1 AND name = $2",
+		[]any{42, "alice"},
+	)
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.True(t, sqlerr.IsNoRows(err))
+	require.Equal(
+		t,
+		`query "SELECT * FROM users WHERE id = $1 AND name = $2": sql: no rows in result set`,
+		err.Error(),
+	)
+	require.NotContains(t, err.Error(), "alice")
+
+	var queryErr *sqlerr.QueryError
+	require.True(t, errors.As(err, &queryErr))
+	require.Equal(t, []string{"int", "string"}, queryErr.ArgTypes)
+
+	require.NoError(t, sqlerr.WrapQuery(nil, "SELECT 1", nil))
+}
+
+func TestWrapQuery_Truncated(t *testing.T) {
+	query := "SELECT " + strings.Repeat("x, ", sqlerr.MaxQueryLen)
+	err := sqlerr.WrapQuery(errors.New("foo"), query, nil)
+
+	var queryErr *sqlerr.QueryError
+	require.True(t, errors.As(err, &queryErr))
+	require.Len(t, queryErr.Query, sqlerr.MaxQueryLen+len("..."))
+	require.True(t, strings.HasSuffix(queryErr.Query, "..."))
+	require.Nil(t, queryErr.ArgTypes)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package sqlerr provides database/sql error classification helpers.
+//
+// Driver-specific errors are classified into portable kinds by adapters, and
+// can be queried with Category. Adapters for drivers that expose SQLSTATE
+// codes via a SQLState() string method (such as github.com/lib/pq and
+// github.com/jackc/pgx) and for github.com/go-sql-driver/mysql are built in;
+// additional adapters can be registered with RegisterAdapter.
+package sqlerr
+
+import (
+	"database/sql"
+	"sync"
+
+	"go.mway.dev/errors"
+)
+
+// A Kind is a portable, driver-independent classification of a database
+// error.
+type Kind int
+
+// Error kinds.
+const (
+	// Unknown indicates that an error could not be classified.
+	Unknown Kind = iota
+	// SerializationFailure indicates that a transaction could not be
+	// serialized with concurrent transactions and may be retried.
+	SerializationFailure
+	// Deadlock indicates that a transaction was aborted due to a deadlock and
+	// may be retried.
+	Deadlock
+	// UniqueViolation indicates that a unique constraint was violated.
+	UniqueViolation
+	// ForeignKeyViolation indicates that a foreign key constraint was
+	// violated.
+	ForeignKeyViolation
+	// NotNullViolation indicates that a not-null constraint was violated.
+	NotNullViolation
+	// CheckViolation indicates that a check constraint was violated.
+	CheckViolation
+)
+
+var _kindNames = [...]string{
+	Unknown:              "unknown",
+	SerializationFailure: "serialization failure",
+	Deadlock:             "deadlock",
+	UniqueViolation:      "unique violation",
+	ForeignKeyViolation:  "foreign key violation",
+	NotNullViolation:     "not null violation",
+	CheckViolation:       "check violation",
+}
+
+// String returns a human-readable representation of k.
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(_kindNames) {
+		return _kindNames[k]
+	}
+	return _kindNames[Unknown]
+}
+
+// An Adapter classifies driver-specific errors. An Adapter should return
+// false if it does not recognize err.
+type Adapter func(err error) (Kind, bool)
+
+var _adapters struct {
+	list []Adapter
+	mu   sync.RWMutex
+}
+
+// RegisterAdapter registers an Adapter used to classify driver-specific
+// errors. Adapters are consulted in reverse registration order, and before
+// built-in adapters, such that later registrations take precedence.
+func RegisterAdapter(adapter Adapter) {
+	if adapter == nil {
+		panic("sqlerr: RegisterAdapter adapter is nil")
+	}
+
+	_adapters.mu.Lock()
+	defer _adapters.mu.Unlock()
+
+	_adapters.list = append(_adapters.list, adapter)
+}
+
+// IsNoRows reports whether err's chain contains sql.ErrNoRows.
+func IsNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// IsTxDone reports whether err's chain contains sql.ErrTxDone.
+func IsTxDone(err error) bool {
+	return errors.Is(err, sql.ErrTxDone)
+}
+
+// IsSerializationFailure reports whether err represents a serialization
+// failure, as classified by the registered adapters.
+func IsSerializationFailure(err error) bool {
+	return Category(err) == SerializationFailure
+}
+
+// Category returns the portable Kind of err, as classified by registered
+// adapters followed by built-in adapters. If err cannot be classified,
+// Category returns Unknown.
+func Category(err error) Kind {
+	if err == nil {
+		return Unknown
+	}
+
+	_adapters.mu.RLock()
+	defer _adapters.mu.RUnlock()
+
+	for i := len(_adapters.list) - 1; i >= 0; i-- {
+		if kind, ok := _adapters.list[i](err); ok {
+			return kind
+		}
+	}
+
+	for _, adapter := range _builtinAdapters {
+		if kind, ok := adapter(err); ok {
+			return kind
+		}
+	}
+
+	return Unknown
+}
+
+// SQLState returns the SQLSTATE code of the first error in err's chain that
+// has a SQLState() string method, if any.
+func SQLState(err error) (string, bool) {
+	var stater interface{ SQLState() string }
+	if !errors.As(err, &stater) {
+		return "", false
+	}
+	return stater.SQLState(), true
+}
+
+var _builtinAdapters = [...]Adapter{
+	sqlStateAdapter,
+	mysqlAdapter,
+}
+
+func sqlStateAdapter(err error) (Kind, bool) {
+	state, ok := SQLState(err)
+	if !ok {
+		return Unknown, false
+	}
+
+	switch state {
+	case "40001":
+		return SerializationFailure, true
+	case "40P01":
+		return Deadlock, true
+	case "23505":
+		return UniqueViolation, true
+	case "23503":
+		return ForeignKeyViolation, true
+	case "23502":
+		return NotNullViolation, true
+	case "23514":
+		return CheckViolation, true
+	default:
+		return Unknown, false
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package sqlerr_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/sqlerr"
+)
+
+func TestIsNoRows(t *testing.T) {
+	require.True(t, sqlerr.IsNoRows(errors.Wrap(sql.ErrNoRows, "get user")))
+	require.False(t, sqlerr.IsNoRows(sql.ErrTxDone))
+	require.False(t, sqlerr.IsNoRows(nil))
+}
+
+func TestIsTxDone(t *testing.T) {
+	require.True(t, sqlerr.IsTxDone(errors.Wrap(sql.ErrTxDone, "commit")))
+	require.False(t, sqlerr.IsTxDone(sql.ErrNoRows))
+	require.False(t, sqlerr.IsTxDone(nil))
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	require.True(t, sqlerr.IsSerializationFailure(
+		errors.Wrap(&stateError{state: "40001"}, "commit"),
+	))
+	require.False(t, sqlerr.IsSerializationFailure(&stateError{state: "23505"}))
+	require.False(t, sqlerr.IsSerializationFailure(errors.New("foo")))
+	require.False(t, sqlerr.IsSerializationFailure(nil))
+}
+
+func TestRegisterAdapter(t *testing.T) {
+	errCustom := errors.New("custom serialization failure")
+	sqlerr.RegisterAdapter(func(err error) (sqlerr.Kind, bool) {
+		if errors.Is(err, errCustom) {
+			return sqlerr.SerializationFailure, true
+		}
+		return sqlerr.Unknown, false
+	})
+
+	require.True(t, sqlerr.IsSerializationFailure(errors.Wrap(errCustom, "commit")))
+	require.Panics(t, func() {
+		sqlerr.RegisterAdapter(nil)
+	})
+}
+
+func TestSQLState(t *testing.T) {
+	state, ok := sqlerr.SQLState(errors.Wrap(&stateError{state: "40001"}, "commit"))
+	require.True(t, ok)
+	require.Equal(t, "40001", state)
+
+	_, ok = sqlerr.SQLState(errors.New("foo"))
+	require.False(t, ok)
+}
+
+func TestCategory(t *testing.T) {
+	cases := map[string]sqlerr.Kind{
+		"40001": sqlerr.SerializationFailure,
+		"40P01": sqlerr.Deadlock,
+		"23505": sqlerr.UniqueViolation,
+		"23503": sqlerr.ForeignKeyViolation,
+		"23502": sqlerr.NotNullViolation,
+		"23514": sqlerr.CheckViolation,
+		"42601": sqlerr.Unknown,
+	}
+
+	for state, want := range cases {
+		t.Run(state, func(t *testing.T) {
+			err := errors.Wrap(&stateError{state: state}, "exec")
+			require.Equal(t, want, sqlerr.Category(err))
+		})
+	}
+
+	require.Equal(t, sqlerr.Unknown, sqlerr.Category(nil))
+	require.Equal(t, sqlerr.Unknown, sqlerr.Category(errors.New("foo")))
+}
+
+func TestKind_String(t *testing.T) {
+	require.Equal(t, "unknown", sqlerr.Unknown.String())
+	require.Equal(t, "serialization failure", sqlerr.SerializationFailure.String())
+	require.Equal(t, "unique violation", sqlerr.UniqueViolation.String())
+	require.Equal(t, "unknown", sqlerr.Kind(-1).String())
+	require.Equal(t, "unknown", sqlerr.Kind(1234).String())
+}
+
+type stateError struct {
+	state string
+}
+
+func (e *stateError) Error() string {
+	return "sqlstate " + e.state
+}
+
+func (e *stateError) SQLState() string {
+	return e.state
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithTime returns a new error that wraps err and records the current time as
+// the time at which err occurred (see [WithTimestamp]). If err is nil, WithTime
+// returns nil.
+func WithTime(err error) error {
+	return WithTimestamp(err, time.Now())
+}
+
+// WithTimestamp returns a new error that wraps err and records t as the time
+// at which err occurred, which can be retrieved with [Time], e.g. so that
+// errors stored for later inspection carry when they happened. The time is
+// included in the %+v rendering of the returned error and in its structured
+// representation (see [Describe]), but not in its message. If err is nil,
+// WithTimestamp returns nil.
+func WithTimestamp(err error, t time.Time) error {
+	if err == nil {
+		return nil
+	}
+	return &timeError{
+		err:  err,
+		time: t,
+	}
+}
+
+// Time returns the time recorded by the first error in err's chain created by
+// [WithTime] or [WithTimestamp], if any.
+func Time(err error) (time.Time, bool) {
+	x, ok := AsType[*timeError](err)
+	if !ok {
+		return time.Time{}, false
+	}
+	return x.time, true
+}
+
+// WithDuration returns a new error that wraps err and records d as the time
+// taken by the operation that failed with err, which can be retrieved with
+// [Duration]. The duration is included in the %+v rendering of the returned
+// error and in its structured representation (see [Describe]), but not in its
+// message. If err is nil, WithDuration returns nil.
+func WithDuration(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &durationError{
+		err:      err,
+		duration: d,
+	}
+}
+
+// Duration returns the duration recorded by the first error in err's chain
+// created by [WithDuration] or [Timed], if any.
+func Duration(err error) (time.Duration, bool) {
+	x, ok := AsType[*durationError](err)
+	if !ok {
+		return 0, false
+	}
+	return x.duration, true
+}
+
+// Timed evaluates fn and, if it returns an error, returns that error with the
+// time taken by fn attached using [WithDuration]. If fn is nil or returns a
+// nil error, Timed returns nil.
+func Timed(fn ErrorFunc) error {
+	if fn == nil {
+		return nil
+	}
+
+	start := time.Now()
+	if err := fn(); err != nil {
+		return WithDuration(err, time.Since(start))
+	}
+	return nil
+}
+
+type timeError struct {
+	time time.Time
+	err  error
+}
+
+func (e *timeError) Unwrap() error {
+	return e.err
+}
+
+func (e *timeError) Error() string {
+	return e.err.Error()
+}
+
+// Format implements fmt.Formatter. The %+v verb renders the %+v rendering of
+// the wrapped error followed by the recorded time; all other verbs render the
+// message.
+func (e *timeError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%+v\noccurred at %s", e.err, e.time.Format(time.RFC3339Nano))
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+}
+
+type durationError struct {
+	err      error
+	duration time.Duration
+}
+
+func (e *durationError) Unwrap() error {
+	return e.err
+}
+
+func (e *durationError) Error() string {
+	return e.err.Error()
+}
+
+// Format implements fmt.Formatter. The %+v verb renders the %+v rendering of
+// the wrapped error followed by the recorded duration; all other verbs render
+// the message.
+func (e *durationError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%+v\nfailed after %s", e.err, e.duration)
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+)
+
+func TestWithTimestamp(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	require.NoError(t, errors.WithTimestamp(nil, ts))
+	require.NoError(t, errors.WithTime(nil))
+
+	err := errors.WithTimestamp(io.EOF, ts)
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	got, ok := errors.Time(errors.Wrap(err, "wrap"))
+	require.True(t, ok)
+	require.Equal(t, ts, got)
+
+	got, ok = errors.Time(io.EOF)
+	require.False(t, ok)
+	require.Zero(t, got)
+}
+
+func TestWithTime(t *testing.T) {
+	before := time.Now()
+	got, ok := errors.Time(errors.WithTime(io.EOF))
+	require.True(t, ok)
+	require.False(t, got.Before(before))
+	require.False(t, got.After(time.Now()))
+}
+
+func TestWithTimestamp_Format(t *testing.T) {
+	err := errors.WithTimestamp(io.EOF, time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC))
+
+	require.Equal(t, "EOF", fmt.Sprintf("%v", err))
+	require.Equal(t, `"EOF"`, fmt.Sprintf("%q", err))
+	require.Equal(t, "EOF\noccurred at 2023-01-02T03:04:05.000000006Z", fmt.Sprintf("%+v", err))
+	require.Equal(
+		t,
+		"read: EOF\noccurred at 2023-01-02T03:04:05.000000006Z",
+		fmt.Sprintf("%+v", errors.Wrap(err, "read")),
+	)
+}
+
+func TestWithTimestamp_Describe(t *testing.T) {
+	var (
+		ts  = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		err = errors.WithTimestamp(errors.WithTimestamp(io.EOF, ts.Add(-time.Hour)), ts)
+	)
+
+	raw, jsonErr := json.Marshal(errors.Describe(err))
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{
+		"message": "EOF",
+		"type": "*errors.errorString",
+		"time": "2023-01-02T03:04:05Z"
+	}`, string(raw))
+}
+
+func TestWithDuration(t *testing.T) {
+	require.NoError(t, errors.WithDuration(nil, time.Second))
+
+	err := errors.WithDuration(io.EOF, 30*time.Second)
+	require.EqualError(t, err, "EOF")
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, "EOF\nfailed after 30s", fmt.Sprintf("%+v", err))
+	require.Equal(t, `"EOF"`, fmt.Sprintf("%q", err))
+
+	got, ok := errors.Duration(errors.Wrap(err, "wrap"))
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, got)
+
+	got, ok = errors.Duration(io.EOF)
+	require.False(t, ok)
+	require.Zero(t, got)
+
+	raw, jsonErr := json.Marshal(errors.Describe(err))
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{
+		"message": "EOF",
+		"type": "*errors.errorString",
+		"duration": "30s"
+	}`, string(raw))
+}
+
+func TestTimed(t *testing.T) {
+	require.NoError(t, errors.Timed(nil))
+	require.NoError(t, errors.Timed(func() error { return nil }))
+
+	err := errors.Timed(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return io.EOF
+	})
+	require.ErrorIs(t, err, io.EOF)
+
+	got, ok := errors.Duration(err)
+	require.True(t, ok)
+	require.GreaterOrEqual(t, got, 10*time.Millisecond)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+// Package validate provides validation errors that identify the invalid
+// fields of a value by path, e.g. "user.email" or "items[3].qty".
+package validate
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"go.mway.dev/errors"
+)
+
+// A FieldError describes why the field of a value at Path is invalid.
+type FieldError struct {
+	// Value is the invalid value, if any.
+	Value any `json:"value,omitempty"`
+	// Path is the path of the field, e.g. "items[3].qty" (see Path).
+	Path string `json:"field"`
+	// Msg describes why the field is invalid, e.g. "must be > 0".
+	Msg string `json:"message"`
+}
+
+// Field returns a new *FieldError for the field at path.
+func Field(path string, value any, msg string) *FieldError {
+	return &FieldError{
+		Value: value,
+		Path:  path,
+		Msg:   msg,
+	}
+}
+
+// Error returns the field error message, e.g. "user.email: invalid".
+func (e *FieldError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Msg
+	}
+	return e.Path + ": " + e.Msg
+}
+
+// Errors are the field errors of an invalid value.
+type Errors []*FieldError
+
+// Error returns the field error messages separated by semicolons, e.g.
+// "user.email: invalid; items[3].qty: must be > 0".
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the field errors, so that they can be inspected with
+// errors.Is and errors.As.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// MarshalJSON encodes the field errors as an object with an "errors" list, as
+// commonly used by API validation responses, e.g.:
+//
+//	{"errors":[{"field":"user.email","message":"invalid"}]}
+func (e Errors) MarshalJSON() ([]byte, error) {
+	fields := []*FieldError(e)
+	if fields == nil {
+		fields = []*FieldError{}
+	}
+
+	return json.Marshal(struct {
+		Errors []*FieldError `json:"errors"`
+	}{
+		Errors: fields,
+	})
+}
+
+// Join returns the field errors among errs, including those of any Errors,
+// as a single Errors, skipping nil errors. If errs contains errors that are
+// not field errors, they are joined with the resulting Errors using
+// errors.Join. If errs contains no non-nil errors, Join returns nil.
+func Join(errs ...error) error {
+	fields, others := split(errs)
+	switch {
+	case len(others) == 0 && len(fields) == 0:
+		return nil
+	case len(others) == 0:
+		return fields
+	case len(fields) == 0 && len(others) == 1:
+		return others[0]
+	case len(fields) > 0:
+		others = append([]error{fields}, others...)
+	}
+	return errors.Join(others...)
+}
+
+// split separates the non-nil field errors in errs from the other non-nil
+// errors.
+func split(errs []error) (fields Errors, others []error) {
+	for _, err := range errs {
+		switch x := err.(type) {
+		case nil:
+		case *FieldError:
+			if x != nil {
+				fields = append(fields, x)
+			}
+		case Errors:
+			for _, field := range x {
+				if field != nil {
+					fields = append(fields, field)
+				}
+			}
+		default:
+			others = append(others, err)
+		}
+	}
+	return fields, others
+}
+
+// Prefix returns err with prefix prepended to the paths of its field errors,
+// e.g. so that the errors from validating a nested value are reported
+// relative to its parent: Prefix("user", err) turns "email" into
+// "user.email", and Prefix("items", err) turns "[3].qty" into
+// "items[3].qty".
+//
+// If err wraps multiple errors, e.g. because it was returned by Join with
+// errors that are not field errors, the field errors among them are prefixed
+// and the result is rebuilt with Join. At most errors.MaxDepth such errors are
+// descended into, which bounds the work done for chains that contain cycles.
+// Other errors are returned unchanged.
+//
+// Nil field errors are discarded; if err is a nil *FieldError or contains only
+// nil field errors, Prefix returns nil.
+func Prefix(prefix string, err error) error {
+	budget := errors.MaxDepth()
+	prefixed, _ := prefixError(prefix, err, &budget)
+	return prefixed
+}
+
+// prefixError returns err with prefix prepended to the paths of its field
+// errors, and whether any were found.
+func prefixError(prefix string, err error, budget *int) (error, bool) { //nolint:revive
+	switch x := err.(type) {
+	case *FieldError:
+		if x == nil {
+			return nil, true
+		}
+		return prefixField(prefix, x), true
+	case Errors:
+		prefixed := make(Errors, 0, len(x))
+		for _, field := range x {
+			if field != nil {
+				prefixed = append(prefixed, prefixField(prefix, field))
+			}
+		}
+		if len(prefixed) == 0 {
+			return nil, true
+		}
+		return prefixed, true
+	case interface{ Unwrap() []error }:
+		if *budget <= 0 {
+			return err, false
+		}
+		*budget--
+		return prefixJoined(prefix, err, x.Unwrap(), budget)
+	default:
+		return err, false
+	}
+}
+
+// prefixJoined returns err, which wraps errs, rebuilt with Join if any of errs
+// contain field errors.
+func prefixJoined( //nolint:revive
+	prefix string,
+	err error,
+	errs []error,
+	budget *int,
+) (error, bool) {
+	var (
+		rebuilt = make([]error, len(errs))
+		changed bool
+	)
+	for i, cause := range errs {
+		var ok bool
+		rebuilt[i], ok = prefixError(prefix, cause, budget)
+		changed = changed || ok
+	}
+	if !changed {
+		return err, false
+	}
+	return Join(rebuilt...), true
+}
+
+func prefixField(prefix string, field *FieldError) *FieldError {
+	prefixed := *field
+	prefixed.Path = joinPath(prefix, field.Path)
+	return &prefixed
+}
+
+// Path returns the path formed by the given elements, which are rendered as
+// field names if they are strings and as indexes if they are ints, e.g.
+// Path("items", 3, "qty") returns "items[3].qty". Elements of any other type
+// are ignored.
+func Path(elems ...any) string {
+	var path string
+	for _, elem := range elems {
+		switch x := elem.(type) {
+		case int:
+			path += "[" + strconv.Itoa(x) + "]"
+		case string:
+			path = joinPath(path, x)
+		}
+	}
+	return path
+}
+
+func joinPath(prefix string, path string) string {
+	switch {
+	case len(prefix) == 0:
+		return path
+	case len(path) == 0:
+		return prefix
+	case path[0] == '[':
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
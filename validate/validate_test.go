@@ -0,0 +1,191 @@
+// Copyright (c) 2023 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package validate_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/errors"
+	"go.mway.dev/errors/validate"
+)
+
+func TestFieldError(t *testing.T) {
+	err := validate.Field("email", "x", "invalid")
+	require.EqualError(t, err, "email: invalid")
+	require.EqualError(t, validate.Field("", nil, "empty"), "empty")
+}
+
+func TestJoin(t *testing.T) {
+	var (
+		email = validate.Field("user.email", "x", "invalid")
+		qty   = validate.Field("items[3].qty", -1, "must be > 0")
+		name  = validate.Field("name", "", "required")
+	)
+
+	require.NoError(t, validate.Join())
+	require.NoError(t, validate.Join(nil, nil))
+
+	err := validate.Join(email, nil, validate.Errors{qty, name})
+	require.EqualError(t, err, "user.email: invalid; items[3].qty: must be > 0; name: required")
+	require.ErrorIs(t, err, qty)
+
+	fields, ok := errors.AsType[validate.Errors](err)
+	require.True(t, ok)
+	require.Len(t, fields, 3)
+
+	field, ok := errors.AsType[*validate.FieldError](err)
+	require.True(t, ok)
+	require.Equal(t, email, field)
+
+	err = validate.Join(io.EOF, email)
+	require.EqualError(t, err, "user.email: invalid\nEOF")
+	require.ErrorIs(t, err, io.EOF)
+	require.ErrorIs(t, err, email)
+
+	require.Equal(t, io.EOF, validate.Join(io.EOF))
+
+	var typedNil *validate.FieldError
+	require.NoError(t, validate.Join(typedNil, validate.Errors{nil}))
+	require.EqualError(t, validate.Join(typedNil, name), "name: required")
+}
+
+func TestErrors_MarshalJSON(t *testing.T) {
+	err := validate.Join(
+		validate.Field("user.email", "x", "invalid"),
+		validate.Field("items[3].qty", nil, "required"),
+	)
+
+	raw, jsonErr := json.Marshal(err)
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{"errors":[
+		{"field":"user.email","message":"invalid","value":"x"},
+		{"field":"items[3].qty","message":"required"}
+	]}`, string(raw))
+
+	raw, jsonErr = json.Marshal(validate.Errors(nil))
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{"errors":[]}`, string(raw))
+}
+
+func TestPrefix(t *testing.T) {
+	var (
+		email = validate.Field("email", "x", "invalid")
+		qty   = validate.Field("[3].qty", -1, "must be > 0")
+	)
+
+	require.EqualError(t, validate.Prefix("user", email), "user.email: invalid")
+	require.EqualError(t, email, "email: invalid")
+
+	err := validate.Prefix("order", validate.Join(
+		validate.Prefix("user", email),
+		validate.Prefix("items", qty),
+		validate.Field("", nil, "incomplete"),
+	))
+	require.EqualError(
+		t,
+		err,
+		"order.user.email: invalid; order.items[3].qty: must be > 0; order: incomplete",
+	)
+
+	require.Equal(t, io.EOF, validate.Prefix("user", io.EOF))
+
+	err = validate.Prefix("user", validate.Join(io.EOF, email, qty))
+	require.EqualError(t, err, "user.email: invalid; user[3].qty: must be > 0\nEOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	other := errors.Join(io.EOF, io.ErrUnexpectedEOF)
+	require.Same(t, other, validate.Prefix("user", other))
+
+	var typedNil *validate.FieldError
+	require.NoError(t, validate.Prefix("user", typedNil))
+	require.Equal(
+		t,
+		validate.Errors{validate.Field("user.email", "x", "invalid")},
+		validate.Prefix("user", validate.Errors{nil, email}),
+	)
+	require.NoError(t, validate.Prefix("user", validate.Errors{nil, nil}))
+	require.NoError(t, validate.Prefix("user", validate.Errors{}))
+	require.NoError(t, validate.Prefix("user", errors.Join(validate.Errors{nil}, typedNil)))
+}
+
+func TestPrefix_Cycles(t *testing.T) {
+	email := validate.Field("email", "x", "invalid")
+
+	self := &cyclicJoinError{}
+	self.errs = []error{self, self, io.EOF}
+	require.Same(t, self, validate.Prefix("user", self))
+
+	self.errs = []error{self, email}
+	err := validate.Prefix("user", self)
+	field, ok := errors.AsType[*validate.FieldError](err)
+	require.True(t, ok)
+	require.Equal(t, "user.email", field.Path)
+}
+
+// cyclicJoinError is a joined error that may contain itself.
+type cyclicJoinError struct {
+	errs []error
+}
+
+func (e *cyclicJoinError) Error() string {
+	return "cyclic join"
+}
+
+func (e *cyclicJoinError) Unwrap() []error {
+	return e.errs
+}
+
+func TestPath(t *testing.T) {
+	cases := map[string]struct {
+		want string
+		give []any
+	}{
+		"empty": {
+			give: nil,
+			want: "",
+		},
+		"field": {
+			give: []any{"user"},
+			want: "user",
+		},
+		"nested": {
+			give: []any{"items", 3, "qty"},
+			want: "items[3].qty",
+		},
+		"index": {
+			give: []any{0, "name", 1, 2},
+			want: "[0].name[1][2]",
+		},
+		"other types": {
+			give: []any{"items", 3.5, int64(3), "qty"},
+			want: "items.qty",
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, validate.Path(tt.give...))
+		})
+	}
+}